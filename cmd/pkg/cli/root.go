@@ -42,11 +42,21 @@ var (
 	outFlag     = "output"
 	inFlag      = "input"
 	pkgFlag     = "package"
+	verboseFlag = "verbose"
+	commentFlag = "comments"
+	debugFlag   = "debug-lines"
+	formatFlag  = "format"
+	copyFlag    = "copy-path"
 
 	displayHelp = "display preview in terminal, the results of parsing (not templated)"
 	inputHelp   = "path to input file"
 	outputHelp  = "path to output file"
 	pkgHelp     = "output file package name"
+	verboseHelp = "log the progress of lexing and parsing copybooks"
+	commentHelp = "keep copybook comments as doc comments on the generated fields"
+	debugHelp   = "read debugging lines, with D in column 7, as source"
+	formatHelp  = "source format of copybooks: auto, fixed or free"
+	copyHelp    = "directory to find the copybooks named by COPY statements in, after the copybook's own; may be repeated"
 )
 
 // Execute executes the root command.
@@ -55,6 +65,12 @@ func Execute() error {
 }
 
 func init() { // nolint:gochecknoinits
+	rootCmd.PersistentFlags().BoolP(verboseFlag, "v", false, verboseHelp)
+	rootCmd.PersistentFlags().Bool(commentFlag, false, commentHelp)
+	rootCmd.PersistentFlags().Bool(debugFlag, false, debugHelp)
+	rootCmd.PersistentFlags().String(formatFlag, "auto", formatHelp)
+	rootCmd.PersistentFlags().StringArray(copyFlag, nil, copyHelp)
+
 	dirCmd.Flags().BoolP(displayFlag, "d", false, displayHelp)
 	dirCmd.Flags().StringP(outFlag, "o", "", outputHelp)
 	dirCmd.Flags().StringP(inFlag, "i", "", inputHelp)
@@ -94,6 +110,11 @@ func dirRun(cmd *cobra.Command, _ []string) error { // nolint:gocyclo
 	}
 
 	d, _ := cmd.Flags().GetBool(displayFlag)
+	lg := logger(cmd)
+	opts, err := lexOptions(cmd, lg, in)
+	if err != nil {
+		return err
+	}
 
 	fs, err := ioutil.ReadDir(in)
 	if err != nil {
@@ -113,13 +134,13 @@ func dirRun(cmd *cobra.Command, _ []string) error { // nolint:gocyclo
 			continue
 		}
 
-		log.Printf("parsing copybook file %s", ff.Name())
+		logf(lg, "parsing copybook file %s", ff.Name())
 		f, err := os.Open(filepath.Join(in, ff.Name())) // nolint:gosec
 		if err != nil {
 			return fmt.Errorf("failed to open file %s: %w", ff.Name(), err)
 		}
 
-		if err := run(f, filepath.Join(out, ff.Name()), pkg, d); err != nil {
+		if err := run(f, filepath.Join(out, ff.Name()), pkg, d, opts...); err != nil {
 			return err
 		}
 	}
@@ -144,17 +165,70 @@ func fileRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	d, _ := cmd.Flags().GetBool(displayFlag)
+	lg := logger(cmd)
+	opts, err := lexOptions(cmd, lg, filepath.Dir(in))
+	if err != nil {
+		return err
+	}
 
-	log.Printf("parsing copybook file %s", in)
+	logf(lg, "parsing copybook file %s", in)
 	f, err := os.Open(in) // nolint:gosec
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %w", in, err)
 	}
 
-	return run(f, out, pkg, d)
+	return run(f, out, pkg, d, opts...)
+}
+
+// logger returns the Logger that progress is reported to, which is nil, so
+// nothing is logged, unless the verbose flag is set.
+func logger(cmd *cobra.Command) lex.Logger {
+	if v, _ := cmd.Flags().GetBool(verboseFlag); v {
+		return log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	return nil
+}
+
+// lexOptions returns the options for lexing copybooks, as set by the flags.
+// The members named by COPY statements are found in dir, the directory of the
+// copybooks, and then in those given by the copy-path flag.
+func lexOptions(cmd *cobra.Command, lg lex.Logger, dir string) ([]lex.Option, error) {
+	opts := []lex.Option{lex.WithLogger(lg)}
+	if c, _ := cmd.Flags().GetBool(commentFlag); c {
+		opts = append(opts, lex.WithComments())
+	}
+
+	if d, _ := cmd.Flags().GetBool(debugFlag); d {
+		opts = append(opts, lex.WithDebugLines())
+	}
+
+	f, _ := cmd.Flags().GetString(formatFlag)
+	switch f {
+	case "auto":
+	case "fixed":
+		opts = append(opts, lex.WithFormat(lex.FixedFormat))
+	case "free":
+		opts = append(opts, lex.WithFormat(lex.FreeFormat))
+	default:
+		return nil, fmt.Errorf("invalid value %q for flag %s, want auto, fixed or free", f, formatFlag)
+	}
+
+	paths, _ := cmd.Flags().GetStringArray(copyFlag)
+	for _, p := range append([]string{dir}, paths...) {
+		opts = append(opts, lex.WithCopybooks(os.DirFS(p)))
+	}
+
+	return opts, nil
 }
 
-func run(r io.Reader, output, pkg string, preview bool) error {
+func logf(lg lex.Logger, format string, v ...interface{}) {
+	if lg != nil {
+		lg.Printf(format, v...)
+	}
+}
+
+func run(r io.Reader, output, pkg string, preview bool, opts ...lex.Option) error {
 	name := strings.TrimSuffix(output, filepath.Ext(output))
 	n := name[strings.LastIndex(name, "/")+1:]
 
@@ -165,9 +239,13 @@ func run(r io.Reader, output, pkg string, preview bool) error {
 		return fmt.Errorf("failed to read input data: %w", err)
 	}
 
-	tree := lex.NewTree(lex.New(n, string(b)))
+	tree := lex.NewTree(lex.New(n, string(b), opts...))
 	time.Sleep(time.Millisecond)
-	c.Root = tree.Parse()
+	c.Root, err = tree.Parse()
+	if err != nil {
+		return fmt.Errorf("failed to parse copybook: %w", err)
+	}
+
 	// TODO: (pgmitche) if record in tree is struct but has no children,
 	// it should probably be ignored entirely
 	if preview {
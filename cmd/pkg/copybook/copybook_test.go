@@ -21,10 +21,10 @@ func Test_Build(t *testing.T) {
 		{
 			name: "ExampleData_BasicInput",
 			input: strings.NewReader(`000160     05  DUMMY-GROUP-1.                                           00000115
-000170         10  DUMMY-SUB-GROUP-1.                                00000116
-000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9.               00000117
-000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.               00000118
-000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9.               00000119
+000170         10  DUMMY-SUB-GROUP-1.                                   00000116
+000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9.                  00000117
+000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.                  00000118
+000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9.                  00000119
 `),
 		}, {
 			name: "ExampleData_FullFile",
@@ -65,6 +65,12 @@ func Test_Build(t *testing.T) {
 001310         10  DUMMY-GROUP-2-OBJECT-G       PIC X(12).              00000243
 001320         10  DUMMY-GROUP-2-OBJECT-H       PIC X(12).              00000244
 `),
+		}, {
+			name: "ConditionValuesNeedingEscapes",
+			input: strings.NewReader(`       01  EXAMPLE.
+           05  STATUS-CD        PIC X(8).
+               88  STATUS-OK    VALUE 'A,B' 'SAY "HI"' 'A` + "`" + `B' 'C\D'.
+`),
 		},
 	}
 
@@ -78,7 +84,8 @@ func Test_Build(t *testing.T) {
 
 			lxr := lex.New(tt.name, string(b))
 			tree := lex.NewTree(lxr)
-			c.Root = tree.Parse()
+			c.Root, err = tree.Parse()
+			require.NoError(t, err)
 
 			var buf bytes.Buffer
 			require.NoError(t, c.WriteToStruct(&buf))
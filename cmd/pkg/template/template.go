@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
 
+	pic "github.com/foundatn-io/go-pic"
 	"github.com/foundatn-io/go-pic/pkg/lex"
 )
 
@@ -25,8 +28,10 @@ func getTemplateFuncs() template.FuncMap {
 		"sanitiseName": sanitiseName,
 		"indexComment": indexComment,
 		"isStruct":     isStruct,
+		"fillerType":   fillerType,
 		"buildStruct":  buildStruct,
 		"getStructs":   getStructs,
+		"docComment":   docComment,
 	}
 }
 
@@ -46,11 +51,15 @@ package {{ .Package }}
 // {{ .Root.Name }} contains a representation of your provided Copybook
 type {{ .Root.Name }} struct {
 	{{- range $element := .Root.Children}}
-		{{- if isStruct $element }}
-			{{- sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs}}
+		{{- if $element.Filler }}
+			_ {{ fillerType $element }} {{ picTag $element.Length $element.Occurs false nil }}{{ indexComment $element.Length $element.Occurs -}}
+		{{- else if isStruct $element }}
+			{{- if $element.Doc }}
+			{{ docComment $element }}
+			{{- end }}{{ sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs $element.Edited $element.Values }}
             {{- buildStruct $element }} 
 		{{ else }}
-			{{ sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs}}{{ indexComment $element.Length $element.Occurs -}} 
+			{{ docComment $element }}{{ sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs $element.Edited $element.Values }}{{ indexComment $element.Length $element.Occurs -}} 
 		{{- end }}
 	{{- end }}
 }
@@ -94,11 +103,38 @@ func goType(l *lex.Record) string {
 	return tag
 }
 
-func picTag(l int, i int) string {
+// fillerType is the type of the blank (_) field emitted for a FILLER. Blank
+// fields are never decoded, so a FILLER group needs no struct of its own.
+func fillerType(l *lex.Record) string {
+	if l.Occurs > 0 {
+		return "[]string"
+	}
+
+	return "string"
+}
+
+func picTag(l int, i int, edited bool, values []string) string {
+	opts := ""
 	if i > 0 {
-		return "`" + fmt.Sprintf("pic:\"%d,%d\"", l, i) + "`"
+		opts += fmt.Sprintf(",%d", i)
+	}
+
+	if edited {
+		opts += ",edited"
+	}
+
+	tag := fmt.Sprintf("pic:\"%d%s\"", l, opts)
+
+	if len(values) > 0 {
+		tag += " values:" + strconv.Quote(pic.JoinValues(values))
 	}
-	return "`" + fmt.Sprintf("pic:\"%d\"", l) + "`"
+
+	// a raw string can't hold a backtick, so the tag must be quoted instead
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+
+	return "`" + tag + "`"
 }
 
 // FIXME: (pgmitche) index comments are being overcalculated now,
@@ -122,6 +158,21 @@ func indexComment(l int, i int) string {
 	return fmt.Sprintf(" // start:%d end:%d", s, endPos-1)
 }
 
+// docComment renders the comments kept from the copybook for a record, if
+// any, as the doc comment of its field.
+func docComment(r *lex.Record) string {
+	if r.Doc == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, l := range strings.Split(r.Doc, "\n") {
+		b.WriteString("// " + l + "\n")
+	}
+
+	return b.String()
+}
+
 func sanitiseName(s string) string {
 	return special.ReplaceAllString(s, "")
 }
@@ -143,11 +194,13 @@ func getStructTemplate() *template.Template {
 // {{ sanitiseName .Name }} contains a representation of the nested group {{ .Name }}
 type {{ sanitiseName .Name }} struct {
 	{{- range $element := .Children}}
-		{{- if isStruct $element }}
-			{{ sanitiseName $element.Name }} {{ goType $element -}} {{ picTag $element.Length $element.Occurs}} 
+		{{- if $element.Filler }}
+			_ {{ fillerType $element }} {{ picTag $element.Length $element.Occurs false nil }} {{ indexComment $element.Length $element.Occurs -}}
+		{{- else if isStruct $element }}
+			{{ docComment $element }}{{ sanitiseName $element.Name }} {{ goType $element -}} {{ picTag $element.Length $element.Occurs $element.Edited $element.Values }} 
             {{- buildStruct $element }} 
 		{{- else }}
-			{{ sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs}} {{ indexComment $element.Length $element.Occurs -}}
+			{{ docComment $element }}{{ sanitiseName $element.Name }} {{ goType $element }} {{ picTag $element.Length $element.Occurs $element.Edited $element.Values }} {{ indexComment $element.Length $element.Occurs -}}
 		{{- end }}
 	{{- end }}
 }`)
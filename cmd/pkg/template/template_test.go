@@ -1 +1,55 @@
 package template
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pic "github.com/foundatn-io/go-pic"
+)
+
+func Test_picTag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		values []string
+	}{
+		{name: "Plain", values: []string{"A", "C", "D"}},
+		{name: "Comma", values: []string{"A,B", "C"}},
+		{name: "DoubleQuote", values: []string{`SAY "HI"`, "C"}},
+		{name: "Backtick", values: []string{"A`B", "C"}},
+		{name: "Backslash", values: []string{`A\B`, `C\,`}},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			src := "package p\n\ntype T struct {\n\tF string " + picTag(1, 0, false, tt.values) + "\n}\n"
+			f, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+			require.NoError(t, err, src)
+
+			lit := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List[0].Tag
+			tag, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+
+			got, err := pic.ParseTag(reflect.StructTag(tag))
+			require.NoError(t, err)
+			require.Equal(t, 1, got.Length)
+			require.Equal(t, tt.values, got.Values)
+		})
+	}
+}
+
+func Test_picTag_Options(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "`pic:\"12\"`", picTag(12, 0, false, nil))
+	require.Equal(t, "`pic:\"12,3\"`", picTag(12, 3, false, nil))
+	require.Equal(t, "`pic:\"12,edited\"`", picTag(12, 0, true, nil))
+	require.Equal(t, "`pic:\"12,3,edited\"`", picTag(12, 3, true, nil))
+}
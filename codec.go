@@ -0,0 +1,531 @@
+package pic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxFastDigits is the most digits that can be parsed into an int64, or
+// exactly into a float64 mantissa, without overflow checks
+const maxFastDigits = 15
+
+// pow10 holds the powers of ten that are exactly representable as float64
+var pow10 = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15}
+
+// Codec decodes records of a single struct type. Its layout, with the offset
+// and setter of every field, is compiled once, so that decoding a record
+// works directly on bytes, without walking the type, and without allocating
+// for anything but string and slice field values.
+//
+// A Codec is not safe for concurrent use, as it tracks validation failures
+// while decoding. Compile a Codec per goroutine instead, which is cheap once
+// the type has been compiled.
+type Codec struct {
+	typ reflect.Type
+	ptr reflect.Type
+	ops []op
+	d   *decoder
+}
+
+// op sets a single field of a record.
+type op struct {
+	index  []int         // field index path, from the compiled struct
+	start  int           // 0-based offset of the field in its record
+	end    int           // 0-based, exclusive, end of the field in its record
+	set    byteSetFunc   // setter for the field value, unless it's a slice
+	zero   reflect.Value // value of the field when absent
+	null   NullPolicy    // sentinels that mark the field as absent
+	values []string      // allowed values of the field
+	parent reflect.Type  // struct containing the field, for errors
+	field  string        // name of the field, for errors
+
+	occurs int  // occurs count of a slice field
+	size   int  // length of each occurrence of a slice field
+	elem   []op // ops for each occurrence of a slice field
+
+	transforms []Transform // transforms of the field
+
+	variant  bool // field is an interface with variants
+	selStart int  // 0-based offset of the field selecting the variant
+	selEnd   int  // 0-based, exclusive, end of the field selecting the variant
+}
+
+type byteSetFunc func(c *Codec, o *op, v reflect.Value, b []byte) error
+
+// Compile builds a Codec for the struct type t, or a pointer to it, using the
+// options that would otherwise be given to NewDecoder.
+func Compile(t reflect.Type, opts ...Option) (*Codec, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("pic: cannot compile codec for non-struct type %s", t)
+	}
+
+	d := &decoder{null: NullSpaces}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return newCodec(t, d)
+}
+
+// newCodec builds a Codec for the struct type t, which shares its options and
+// state with the decoder d.
+func newCodec(t reflect.Type, d *decoder) (*Codec, error) {
+	c := &Codec{typ: t, ptr: reflect.PtrTo(t), d: d}
+	root := scope{null: d.null, all: d.fields == nil}
+	seen := make(map[string]struct{}, len(d.fields))
+	ops, err := c.compile(t, root, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range d.fields.paths() {
+		if _, ok := seen[path]; !ok {
+			return nil, fmt.Errorf("pic: %s has no field %s", t, path)
+		}
+	}
+
+	c.ops = ops
+	return c, nil
+}
+
+// projection maps the path of each selected field to true, and the path of
+// each group containing a selected field to false.
+type projection map[string]bool
+
+func newProjection(paths []string) projection {
+	p := make(projection, len(paths))
+	for _, path := range paths {
+		p[path] = true
+		for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+			if _, ok := p[path[:i]]; !ok {
+				p[path[:i]] = false
+			}
+		}
+	}
+
+	return p
+}
+
+// paths returns the selected field paths, in order.
+func (p projection) paths() []string {
+	paths := make([]string, 0, len(p))
+	for path, whole := range p {
+		if whole {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// scope is the position of a struct being compiled within the compiled type.
+type scope struct {
+	offset int        // 0-based offset of the struct
+	index  []int      // field index path of the struct
+	null   NullPolicy // sentinels that mark the struct's fields as absent
+	path   string     // field path prefix of the struct's fields, e.g. Group.
+	all    bool       // every field is decoded, regardless of projection
+}
+
+// compile builds the ops for each field of the struct type t. Nested structs
+// are flattened into the ops of their fields, and fields that aren't selected
+// by the decoder's projection are left out. The paths of the fields visited
+// are added to seen.
+func (c *Codec) compile(t reflect.Type, sc scope, seen map[string]struct{}) ([]op, error) { // nolint:gocyclo
+	spec := cachedStructRepresentation(t)
+	ops := make([]op, 0, len(spec.fields))
+	for i, ff := range spec.fields {
+		f := t.Field(i)
+		if ff.err != nil {
+			return nil, &TagError{f.Type, t.Name(), f.Name, ff.err}
+		}
+
+		if ff.skip {
+			continue
+		}
+
+		path, all := sc.path+f.Name, sc.all
+		if seen != nil && !ff.inline {
+			seen[path] = struct{}{}
+		}
+
+		// inline fields are selected by their own paths, like promoted fields
+		if !all && !ff.inline {
+			whole, ok := c.d.fields[path]
+			if !ok {
+				continue
+			}
+
+			all = whole
+		}
+
+		o := op{
+			index:  append(append(make([]int, 0, len(sc.index)+1), sc.index...), i),
+			start:  sc.offset + ff.start - 1,
+			end:    sc.offset + ff.end,
+			zero:   reflect.Zero(f.Type),
+			null:   sc.null,
+			values: ff.values,
+			parent: t,
+			field:  f.Name,
+			occurs: ff.occurs,
+		}
+
+		if ff.nullOverride {
+			o.null = ff.null
+		}
+
+		if c.d.transforming() {
+			ts, err := c.d.transformsFor(path, ff.transforms)
+			if err != nil {
+				return nil, &TagError{f.Type, t.Name(), f.Name, err}
+			}
+
+			o.transforms = ts
+		}
+
+		switch {
+		case isGroup(f.Type):
+			prefix := path + "."
+			if ff.inline {
+				prefix = sc.path
+			}
+
+			nested, err := c.compile(f.Type, scope{o.start, o.index, o.null, prefix, all}, seen)
+			if err != nil {
+				return nil, err
+			}
+
+			ops = append(ops, nested...)
+			continue
+
+		case f.Type.Kind() == reflect.Slice:
+			o.size = ff.len / ff.occurs
+			elem, err := c.compileElem(f.Type.Elem(), o.size, o.null, ff.edited, path+".")
+			if err != nil {
+				return nil, err
+			}
+
+			o.elem = elem
+
+		case ff.selector >= 0:
+			sel := spec.fields[ff.selector]
+			o.variant = true
+			o.selStart = sc.offset + sel.start - 1
+			o.selEnd = sc.offset + sel.end
+
+		default:
+			o.set = newByteSetFunc(f.Type, ff.edited)
+		}
+
+		ops = append(ops, o)
+	}
+
+	return ops, nil
+}
+
+// compileElem builds the ops for a single occurrence of a slice field, whose
+// fields have paths starting with prefix.
+func (c *Codec) compileElem(t reflect.Type, size int, null NullPolicy, edited bool, prefix string) ([]op, error) {
+	if isGroup(t) {
+		return c.compile(t, scope{null: null, path: prefix, all: true}, nil)
+	}
+
+	return []op{{
+		end:  size,
+		set:  newByteSetFunc(t, edited),
+		zero: reflect.Zero(t),
+		null: null,
+	}}, nil
+}
+
+// DecodeBytes decodes a single record from b into v, which must be a non-nil
+// pointer to the compiled type.
+func (c *Codec) DecodeBytes(b []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	if rv.Type() != c.ptr {
+		return fmt.Errorf("pic: codec for %s cannot decode into %T", c.typ, v)
+	}
+
+	if err := c.apply(c.ops, rv.Elem(), b); err != nil {
+		return err
+	}
+
+	if len(c.d.violations) > 0 {
+		err := c.d.violations
+		c.d.violations = nil
+		return err
+	}
+
+	return nil
+}
+
+func (c *Codec) apply(ops []op, v reflect.Value, rec []byte) error { // nolint:gocyclo
+	for i := range ops {
+		o := &ops[i]
+		f := v
+		if o.index != nil {
+			f = v.FieldByIndex(o.index)
+		}
+
+		raw := fieldBytes(rec, o.start, o.end)
+		b := trimBytes(raw)
+		if o.transforms != nil {
+			s, err := transformRaw(o.transforms, string(b))
+			if err != nil {
+				return &UnmarshalTypeError{string(rec), f.Type(), o.parent.Name(), o.field, err}
+			}
+
+			raw, b = []byte(s), []byte(s)
+		}
+
+		var err error
+		null := o.null.isNullBytes(b)
+		switch {
+		case null:
+			f.Set(o.zero)
+		case o.elem != nil:
+			err = c.setOccurs(o, f, raw)
+		case o.variant:
+			err = c.setVariant(o, f, trimBytes(fieldBytes(rec, o.selStart, o.selEnd)), b)
+		default:
+			err = o.set(c, o, f, b)
+		}
+
+		if err == nil && o.transforms != nil {
+			err = transformValue(o.transforms, f)
+		}
+
+		if err != nil {
+			if o.parent == nil {
+				return err
+			}
+
+			return &UnmarshalTypeError{string(rec), f.Type(), o.parent.Name(), o.field, err}
+		}
+
+		// absent fields have no value to validate, as with a Decoder
+		if c.d.validate && len(o.values) > 0 && !null {
+			if val := string(b); !allowed(o.values, val) {
+				c.d.violations = append(c.d.violations, &ValidationError{val, o.values, o.parent.Name(), o.field})
+			}
+		}
+	}
+
+	return nil
+}
+
+// newByteSetFunc returns a setter for an elementary type, using fast paths for
+// strings and plain numbers, and falling back to the decoder's setters for
+// anything else, including numeric-edited data.
+func newByteSetFunc(t reflect.Type, edited bool) byteSetFunc {
+	if isScanner(t) || edited {
+		return fallbackByteSetFunc(t, edited)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return strByteSetFunc
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intByteSetFunc
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uintByteSetFunc
+	case reflect.Float32, reflect.Float64:
+		return floatByteSetFunc(t.Bits())
+	}
+
+	return fallbackByteSetFunc(t, false)
+}
+
+func strByteSetFunc(_ *Codec, _ *op, v reflect.Value, b []byte) error {
+	v.SetString(string(b))
+	return nil
+}
+
+func intByteSetFunc(c *Codec, _ *op, v reflect.Value, b []byte) error {
+	if n, ok := atoi(b); ok && !v.OverflowInt(n) {
+		v.SetInt(n)
+		return nil
+	}
+
+	return intSetFunc(c.d, v, string(b))
+}
+
+func uintByteSetFunc(c *Codec, _ *op, v reflect.Value, b []byte) error {
+	if n, ok := atoi(b); ok && n >= 0 && !v.OverflowUint(uint64(n)) {
+		v.SetUint(uint64(n))
+		return nil
+	}
+
+	return uintSetFunc(c.d, v, string(b))
+}
+
+func floatByteSetFunc(size int) byteSetFunc {
+	fallback := floatSetFunc(size)
+	return func(c *Codec, _ *op, v reflect.Value, b []byte) error {
+		if f, ok := atof(b); ok {
+			v.SetFloat(f)
+			return nil
+		}
+
+		return fallback(c.d, v, string(b))
+	}
+}
+
+func fallbackByteSetFunc(t reflect.Type, edited bool) byteSetFunc {
+	set := kindSetFunc(t, 0, 0, edited)
+	return func(c *Codec, o *op, v reflect.Value, b []byte) error {
+		null := c.d.null
+		c.d.null = o.null
+		err := set(c.d, v, string(b))
+		c.d.null = null
+		return err
+	}
+}
+
+// setVariant decodes b into the interface field v, as the variant selected by
+// key.
+func (c *Codec) setVariant(o *op, v reflect.Value, key, b []byte) error {
+	null := c.d.null
+	c.d.null = o.null
+	err := c.d.setVariant(v, string(key), string(b))
+	c.d.null = null
+	return err
+}
+
+// setOccurs decodes each occurrence of a slice field from its untrimmed
+// bytes, reusing the slice already held by the field if it has the right
+// length.
+func (c *Codec) setOccurs(o *op, v reflect.Value, raw []byte) error {
+	if v.Len() != o.occurs {
+		v.Set(reflect.MakeSlice(v.Type(), o.occurs, o.occurs))
+	}
+
+	for i := 0; i < o.occurs; i++ {
+		if err := c.apply(o.elem, v.Index(i), fieldBytes(raw, i*o.size, (i+1)*o.size)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldBytes returns the bytes of a field, between the 0-based start and end
+// offsets, which may be cut short by the end of the record.
+func fieldBytes(rec []byte, start, end int) []byte {
+	if start >= len(rec) {
+		return nil
+	}
+
+	if end > len(rec) {
+		end = len(rec)
+	}
+
+	return rec[start:end]
+}
+
+// trimBytes trims surrounding spaces from b, like newValFromLine.
+func trimBytes(b []byte) []byte {
+	for len(b) > 0 && b[0] == ' ' {
+		b = b[1:]
+	}
+
+	for len(b) > 0 && b[len(b)-1] == ' ' {
+		b = b[:len(b)-1]
+	}
+
+	return b
+}
+
+// atoi parses plain, optionally signed, decimal digits without allocating.
+func atoi(b []byte) (int64, bool) {
+	neg, b := sign(b)
+	if len(b) == 0 || len(b) > maxFastDigits {
+		return 0, false
+	}
+
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+
+	if neg {
+		n = -n
+	}
+
+	return n, true
+}
+
+// atof parses plain, optionally signed, decimal digits with an optional
+// decimal point without allocating. The result is exact, as both the digits
+// and the power of ten they're divided by are exactly representable.
+func atof(b []byte) (float64, bool) {
+	neg, b := sign(b)
+	if len(b) == 0 || len(b) > maxFastDigits+1 {
+		return 0, false
+	}
+
+	var n int64
+	digits, scale := 0, -1
+	for i, c := range b {
+		switch {
+		case c == '.' && scale < 0:
+			scale = len(b) - i - 1
+		case c >= '0' && c <= '9':
+			n = n*10 + int64(c-'0')
+			digits++
+		default:
+			return 0, false
+		}
+	}
+
+	if digits == 0 || digits > maxFastDigits {
+		return 0, false
+	}
+
+	f := float64(n)
+	if scale > 0 {
+		f /= pow10[scale]
+	}
+
+	if neg {
+		f = -f
+	}
+
+	return f, true
+}
+
+func sign(b []byte) (bool, []byte) {
+	if len(b) > 0 {
+		switch b[0] {
+		case '-':
+			return true, b[1:]
+		case '+':
+			return false, b[1:]
+		}
+	}
+
+	return false, b
+}
+
+func isScanner(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(scannerType)
+}
@@ -0,0 +1,192 @@
+package pic
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec_DecodeBytes(t *testing.T) {
+	type D struct {
+		DA string `pic:"2"`
+	}
+
+	type C struct {
+		CA []string `pic:"1,5"`
+		CB D        `pic:"2"`
+	}
+
+	type dummy struct {
+		A int `pic:"1"`
+		B int `pic:"1"`
+	}
+
+	type record struct {
+		String  string        `pic:"5"`
+		Int     int           `pic:"5"`
+		Float   float64       `pic:"7"`
+		Edited  float64       `pic:"12,edited"`
+		Uint    uint8         `pic:"3"`
+		_       string        `pic:"2"`
+		Nested  C             `pic:"7"`
+		Occurs  []dummy       `pic:"2,3"`
+		Ptr     *int          `pic:"3"`
+		Null    sql.NullInt64 `pic:"3"`
+		Float32 float32       `pic:"4"`
+	}
+
+	for _, test := range []struct {
+		name string
+		val  string
+	}{
+		{
+			name: "Full",
+			val:  "foo  -123 12.5000  1,234.56CR255XXABCDEAA123456007   1.25",
+		}, {
+			name: "Short",
+			val:  "foo  123",
+		}, {
+			name: "Blank",
+			val:  "                                                            ",
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			want := record{}
+			require.NoError(t, Unmarshal([]byte(tt.val), &want))
+
+			c, err := Compile(reflect.TypeOf(record{}))
+			require.NoError(t, err)
+
+			got := record{}
+			require.NoError(t, c.DecodeBytes([]byte(tt.val), &got))
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCodec_ValidatesLikeDecoder(t *testing.T) {
+	type record struct {
+		Status string `pic:"1" values:"A,C,D"`
+		Type   int    `pic:"2" values:"1,2,3"`
+	}
+
+	for _, test := range []struct {
+		name  string
+		val   string
+		opts  []Option
+		wants int // number of validation errors
+	}{
+		{
+			name: "Valid",
+			val:  "C02",
+		}, {
+			name:  "Invalid",
+			val:   "X09",
+			wants: 2,
+		}, {
+			name: "BlankIsAbsent",
+			val:  "   ",
+		}, {
+			name:  "BlankIsNotAbsent",
+			val:   "   ",
+			opts:  []Option{WithNullPolicy(NullNone)},
+			wants: 2,
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithValidation()}, tt.opts...)
+			c, err := Compile(reflect.TypeOf(record{}), opts...)
+			require.NoError(t, err)
+
+			errs := []error{
+				NewDecoder(strings.NewReader(tt.val), opts...).Decode(&record{}),
+				c.DecodeBytes([]byte(tt.val), &record{}),
+			}
+
+			for _, err := range errs {
+				if tt.wants == 0 {
+					require.NoError(t, err)
+					continue
+				}
+
+				var verrs ValidationErrors
+				require.True(t, errors.As(err, &verrs), "want ValidationErrors, got %v", err)
+				require.Len(t, verrs, tt.wants)
+			}
+		})
+	}
+}
+
+func TestCodec_DecodeBytesAllocations(t *testing.T) {
+	type record struct {
+		A int     `pic:"5"`
+		B uint    `pic:"5"`
+		C float64 `pic:"8"`
+		D []int   `pic:"2,3"`
+	}
+
+	c, err := Compile(reflect.TypeOf(record{}))
+	require.NoError(t, err)
+
+	b := []byte("-1234" + "56789" + " 1234.56" + "123456")
+	got := record{}
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := c.DecodeBytes(b, &got); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	require.Equal(t, record{-1234, 56789, 1234.56, []int{12, 34, 56}}, got)
+	require.Zero(t, allocs)
+}
+
+func TestCodec_Errors(t *testing.T) {
+	type record struct {
+		Status string `pic:"1" values:"A,C,D"`
+		Int    int    `pic:"3"`
+	}
+
+	t.Run("NotStruct", func(t *testing.T) {
+		_, err := Compile(reflect.TypeOf(""))
+		require.EqualError(t, err, "pic: cannot compile codec for non-struct type string")
+	})
+
+	t.Run("SliceWithoutOccurs", func(t *testing.T) {
+		_, err := Compile(reflect.TypeOf(struct {
+			A []string `pic:"5"`
+		}{}))
+		require.EqualError(t, err, "pic: invalid Go struct field .A of type []string: slice field has no occurs count")
+	})
+
+	c, err := Compile(reflect.TypeOf(&record{}), WithValidation())
+	require.NoError(t, err)
+
+	t.Run("WrongTarget", func(t *testing.T) {
+		require.EqualError(t, c.DecodeBytes([]byte("A123"), record{}),
+			"pic: codec for pic.record cannot decode into pic.record")
+	})
+
+	t.Run("NilTarget", func(t *testing.T) {
+		var iue *InvalidUnmarshalError
+		require.True(t, errors.As(c.DecodeBytes([]byte("A123"), nil), &iue))
+		require.True(t, errors.As(c.DecodeBytes([]byte("A123"), (*record)(nil)), &iue))
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		require.EqualError(t, c.DecodeBytes([]byte("Anan"), &record{}),
+			"pic: cannot unmarshal Anan into Go struct field record.Int of type int: "+
+				"failed string->int conversion: strconv.Atoi: parsing \"nan\": invalid syntax")
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		err := c.DecodeBytes([]byte("X123"), &record{})
+		require.IsType(t, ValidationErrors{}, err)
+		require.NoError(t, c.DecodeBytes([]byte("C123"), &record{}))
+	})
+}
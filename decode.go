@@ -3,7 +3,7 @@ package pic
 import (
 	"bufio"
 	"bytes"
-	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
@@ -29,6 +29,20 @@ func Unmarshal(data []byte, v interface{}) error {
 type decoder struct {
 	s    *bufio.Scanner
 	done bool
+	err  error // from an invalid option, returned by Decode and Compile
+
+	validate   bool
+	violations ValidationErrors
+	null       NullPolicy
+
+	fields projection
+	codecs map[reflect.Type]*Codec
+
+	variants map[reflect.Type]map[string]variant
+
+	transforms    map[string][]Transform
+	tagTransforms map[string]Transform
+	prefix        string // path prefix of the fields being decoded
 }
 
 // Decoder ...
@@ -36,20 +50,54 @@ type Decoder interface {
 	Decode(interface{}) error
 }
 
+// Option configures optional behaviour of a Decoder.
+type Option func(*decoder)
+
+// WithValidation enables validation of decoded fields against the allowed
+// values declared in their values tag, e.g. `pic:"1" values:"A,C,D"`.
+// Violations are reported by Decode as ValidationErrors.
+func WithValidation() Option {
+	return func(d *decoder) {
+		d.validate = true
+	}
+}
+
+// WithFields limits decoding to the fields with the given paths, e.g. Name or
+// Group.Items, leaving every other field of the target untouched. Selecting a
+// group selects all of its fields. The bytes of fields that aren't selected
+// are never parsed, which makes picking a few fields out of wide records
+// cheap.
+func WithFields(paths ...string) Option {
+	return func(d *decoder) {
+		d.fields = newProjection(paths)
+	}
+}
+
 // NewDecoder builds a new decoder using a bufio.Scanner for the given input
 // io.Reader.
-func NewDecoder(r io.Reader) Decoder {
-	return &decoder{
-		s: bufio.NewScanner(r),
+func NewDecoder(r io.Reader, opts ...Option) Decoder {
+	d := &decoder{
+		s:    bufio.NewScanner(r),
+		null: NullSpaces,
 	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
 }
 
 // Decode scans through each line of the input data, attempting to unpack its
 // values into the provided destination struct.
 func (d *decoder) Decode(v interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return errors.New("decode: unmarshal target object is not a pointer, or is nil")
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
 	}
 
 	if rv.Elem().Kind() == reflect.Slice {
@@ -71,9 +119,57 @@ func (d *decoder) scanLine(v reflect.Value) (bool, error) {
 	}
 
 	t := v.Type()
+	if d.fields != nil {
+		return true, d.scanFields(v)
+	}
+
+	// the target itself is never absent, only its fields may be
+	set := kindSetFunc(t, 0, 0, false)
+	if err := set(d, v, string(d.s.Bytes())); err != nil {
+		return true, err
+	}
+
+	if len(d.violations) > 0 {
+		err := d.violations
+		d.violations = nil
+		return true, err
+	}
+
+	return true, nil
+}
+
+// scanFields decodes the selected fields of the current line into v, using a
+// Codec compiled for the type of v on first use.
+func (d *decoder) scanFields(v reflect.Value) error {
+	v = reflect.Indirect(v)
+	c, ok := d.codecs[v.Type()]
+	if !ok {
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("pic: cannot decode selected fields into non-struct type %s", v.Type())
+		}
+
+		var err error
+		if c, err = newCodec(v.Type(), d); err != nil {
+			return err
+		}
 
-	set := newSetFunc(t, 0, 0)
-	return true, set(v, string(d.s.Bytes()))
+		if d.codecs == nil {
+			d.codecs = make(map[reflect.Type]*Codec)
+		}
+		d.codecs[v.Type()] = c
+	}
+
+	if err := c.apply(c.ops, v, d.s.Bytes()); err != nil {
+		return err
+	}
+
+	if len(d.violations) > 0 {
+		err := d.violations
+		d.violations = nil
+		return err
+	}
+
+	return nil
 }
 
 func (d *decoder) scanLines(v reflect.Value) (err error) {
@@ -1,7 +1,12 @@
 package pic
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -229,3 +234,426 @@ func TestUnmarshal(t *testing.T) {
 		}
 	})
 }
+
+func TestDecoder_WithValidation(t *testing.T) {
+	type account struct {
+		ID     string `pic:"4"`
+		Status string `pic:"1" values:"A,C,D"`
+		Type   int    `pic:"2" values:"1,2,3"`
+	}
+
+	for _, test := range []struct {
+		name     string
+		val      string
+		opts     []Option
+		expected account
+		err      string
+	}{
+		{
+			name:     "Valid",
+			val:      "0001C02",
+			opts:     []Option{WithValidation()},
+			expected: account{"0001", "C", 2},
+		}, {
+			name:     "NotValidated",
+			val:      "0001X09",
+			expected: account{"0001", "X", 9},
+		}, {
+			name:     "Invalid",
+			val:      "0001X09",
+			opts:     []Option{WithValidation()},
+			expected: account{"0001", "X", 9},
+			err: `pic: value "X" of Go struct field account.Status is not one of the allowed values [A,C,D]; ` +
+				`pic: value "09" of Go struct field account.Type is not one of the allowed values [1,2,3]`,
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			got := account{}
+			err := NewDecoder(strings.NewReader(tt.val), tt.opts...).Decode(&got)
+			require.Equal(t, tt.expected, got)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tt.err)
+			var verrs ValidationErrors
+			require.True(t, errors.As(err, &verrs))
+			require.Len(t, verrs, 2)
+		})
+	}
+}
+
+func TestUnmarshal_SkipFields(t *testing.T) {
+	type withFillers struct {
+		A string `pic:"3"`
+		_ string `pic:"2"`
+		B string `pic:"3"`
+		C string `pic:"4,skip"`
+		_ string `pic:"1"`
+		D int    `pic:"2"`
+	}
+
+	got := withFillers{}
+	require.NoError(t, Unmarshal([]byte("foo  barXXXX-12"), &got))
+	require.Equal(t, withFillers{A: "foo", B: "bar", D: 12}, got)
+}
+
+func TestUnmarshal_Offsets(t *testing.T) {
+	type sparse struct {
+		ID     string `pic:"3"`
+		Amount int    `pic:"4" offset:"10"`
+		Code   string `pic:"2"`
+		Name   string `pic:"4" offset:"5"`
+	}
+
+	got := sparse{}
+	require.NoError(t, Unmarshal([]byte("ABC..NAME.0042XY"), &got))
+	require.Equal(t, sparse{ID: "ABC", Amount: 42, Code: "XY", Name: "NAME"}, got)
+
+	type redefined struct {
+		Date  string `pic:"8"`
+		Year  int    `pic:"4" redefines:"Date"`
+		Month int    `pic:"2" offset:"4" redefines:"Date"`
+		Flag  string `pic:"1"`
+	}
+
+	got2 := redefined{}
+	require.NoError(t, Unmarshal([]byte("20240131Y"), &got2))
+	require.Equal(t, redefined{Date: "20240131", Year: 2024, Month: 1, Flag: "Y"}, got2)
+
+	for _, test := range []struct {
+		name string
+		typ  reflect.Type
+		err  string
+	}{
+		{
+			name: "Overlap",
+			typ: reflect.TypeOf(struct {
+				A string `pic:"5"`
+				B string `pic:"5" offset:"3"`
+			}{}),
+			err: "field B (3-8) overlaps field A (0-5) without redefining it",
+		}, {
+			name: "UnknownRedefines",
+			typ: reflect.TypeOf(struct {
+				A string `pic:"5" redefines:"B"`
+				B string `pic:"5"`
+			}{}),
+			err: "redefined field B is not declared before A",
+		}, {
+			name: "NegativeOffset",
+			typ: reflect.TypeOf(struct {
+				A string `pic:"5" offset:"-1"`
+			}{}),
+			err: "negative offset -1",
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			for _, f := range makeStructRepresentation(tt.typ).fields {
+				if f.err != nil {
+					err = f.err
+				}
+			}
+
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+type Header struct {
+	Kind string `pic:"1"`
+	Seq  int    `pic:"3"`
+}
+
+type trailer struct {
+	Check string `pic:"2"`
+}
+
+func TestUnmarshal_EmbeddedStructs(t *testing.T) {
+	type Body struct {
+		Name string `pic:"4"`
+	}
+
+	type record struct {
+		Header
+		Body   `pic:"5"`
+		Amount int `pic:"3"`
+		trailer
+	}
+
+	data := "A001NAME 042ZZ"
+	expected := record{Header{"A", 1}, Body{"NAME"}, 42, trailer{"ZZ"}}
+
+	got := record{}
+	require.NoError(t, Unmarshal([]byte(data), &got))
+	require.Equal(t, expected, got)
+
+	c, err := Compile(reflect.TypeOf(record{}))
+	require.NoError(t, err)
+
+	got = record{}
+	require.NoError(t, c.DecodeBytes([]byte(data), &got))
+	require.Equal(t, expected, got)
+
+	got = record{}
+	require.NoError(t, NewDecoder(strings.NewReader(data), WithFields("Seq", "Check")).Decode(&got))
+	require.Equal(t, record{Header: Header{Seq: 1}, trailer: trailer{"ZZ"}}, got)
+
+	l, err := LayoutOf(record{})
+	require.NoError(t, err)
+	require.Equal(t, 14, l.Length)
+
+	paths := make([]string, len(l.Fields))
+	for i, f := range l.Fields {
+		paths[i] = fmt.Sprintf("%s@%d", f.Path, f.Offset)
+	}
+
+	require.Equal(t, []string{"Kind@0", "Seq@1", "Body@4", "Body.Name@4", "Amount@9", "Check@12"}, paths)
+}
+
+func TestUnmarshal_EmbeddedPointer(t *testing.T) {
+	type record struct {
+		*Header
+		Amount int `pic:"3"`
+	}
+
+	const msg = "pic: invalid Go struct field record.Header of type *pic.Header: " +
+		"embedded *pic.Header must have a pic tag, or be embedded by value to be laid out in place"
+
+	require.EqualError(t, Unmarshal([]byte("A001042"), &record{}), msg)
+	require.EqualError(t, Validate(record{}), msg)
+
+	_, err := Compile(reflect.TypeOf(record{}))
+	require.EqualError(t, err, msg)
+
+	_, err = LayoutOf(record{})
+	require.EqualError(t, err, msg)
+
+	type tagged struct {
+		*Header `pic:"4"`
+		Amount  int `pic:"3"`
+		*sync.Mutex
+	}
+
+	got := tagged{}
+	require.NoError(t, Unmarshal([]byte("A001042"), &got))
+	require.Equal(t, tagged{Header: &Header{"A", 1}, Amount: 42}, got)
+}
+
+type optionalString struct {
+	Value string
+	Set   bool
+}
+
+func (o *optionalString) Scan(v interface{}) error {
+	if v == nil {
+		*o = optionalString{}
+		return nil
+	}
+
+	*o = optionalString{Value: fmt.Sprint(v), Set: true}
+	return nil
+}
+
+func TestDecoder_WithNullPolicy(t *testing.T) {
+	type nullable struct {
+		A *int           `pic:"3"`
+		B sql.NullInt64  `pic:"3"`
+		C optionalString `pic:"3"`
+		D int            `pic:"3"`
+		E *string        `pic:"3" null:"high-values"`
+	}
+
+	one, abc := 1, "abc"
+	for _, test := range []struct {
+		name     string
+		val      string
+		policy   NullPolicy
+		expected nullable
+		err      bool
+	}{
+		{
+			name:     "Present",
+			val:      "001002abc004abc",
+			policy:   NullSpaces,
+			expected: nullable{&one, sql.NullInt64{Int64: 2, Valid: true}, optionalString{"abc", true}, 4, &abc},
+		}, {
+			name:     "Spaces",
+			val:      "               ",
+			policy:   NullSpaces,
+			expected: nullable{},
+		}, {
+			name:     "LowValues",
+			val:      "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\xff\xff\xff",
+			policy:   NullLowValues,
+			expected: nullable{},
+		}, {
+			name:   "LowValuesNotNull",
+			val:    "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\xff\xff\xff",
+			policy: NullSpaces,
+			err:    true,
+		}, {
+			name:     "HighValues",
+			val:      "\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff",
+			policy:   NullHighValues | NullLowValues,
+			expected: nullable{},
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			got := nullable{}
+			err := NewDecoder(strings.NewReader(tt.val), WithNullPolicy(tt.policy)).Decode(&got)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestDecoder_WithFields(t *testing.T) {
+	type group struct {
+		Name  string `pic:"4"`
+		Count int    `pic:"2"`
+	}
+
+	type record struct {
+		ID     string `pic:"3"`
+		Amount int    `pic:"5"`
+		Group  group  `pic:"6"`
+		Codes  []int  `pic:"1,3"`
+	}
+
+	data := "ABC00012NAME05123\nXYZ-0034ITEM99456\n"
+	for _, test := range []struct {
+		name     string
+		fields   []string
+		expected []record
+		err      string
+	}{
+		{
+			name:     "TopLevel",
+			fields:   []string{"ID", "Codes"},
+			expected: []record{{ID: "ABC", Codes: []int{1, 2, 3}}, {ID: "XYZ", Codes: []int{4, 5, 6}}},
+		}, {
+			name:     "NestedField",
+			fields:   []string{"Group.Count", "Amount"},
+			expected: []record{{Amount: 12, Group: group{Count: 5}}, {Amount: -34, Group: group{Count: 99}}},
+		}, {
+			name:     "WholeGroup",
+			fields:   []string{"Group.Name", "Group"},
+			expected: []record{{Group: group{"NAME", 5}}, {Group: group{"ITEM", 99}}},
+		}, {
+			name:   "UnknownField",
+			fields: []string{"Group.Missing"},
+			err:    "pic: pic.record has no field Group.Missing",
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			got := []record{}
+			err := NewDecoder(strings.NewReader(data), WithFields(tt.fields...)).Decode(&got)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("UnselectedUntouched", func(t *testing.T) {
+		got := record{ID: "OLD", Amount: 7}
+		require.NoError(t, NewDecoder(strings.NewReader(data), WithFields("Amount")).Decode(&got))
+		require.Equal(t, record{ID: "OLD", Amount: 12}, got)
+	})
+}
+
+func TestUnmarshal_NumericEdited(t *testing.T) {
+	type edited struct {
+		A float64 `pic:"12,edited"` // PIC ZZZ,ZZ9.99CR
+		B float64 `pic:"11,edited"` // PIC $**,**9.99-
+		C uint    `pic:"4,edited"`  // PIC ZZZ9
+		D int     `pic:"6,edited"`  // PIC +Z(4)9
+		E int     `pic:"6,edited"`  // PIC ZZZZ9-
+		F float64 `pic:"9,edited"`  // PIC -$$$9.99
+		G uint    `pic:"8,edited"`  // PIC 99/99/99
+		H int     `pic:"6,edited"`  // PIC ZZZ9DB
+	}
+
+	for _, test := range []struct {
+		name     string
+		val      string
+		expected edited
+	}{
+		{
+			name:     "Negative",
+			val:      "  1,234.56CR" + "$***123.45-" + "  12" + "-   12" + "   42-" + "   -$3.50" + "12/31/99" + "  12DB",
+			expected: edited{-1234.56, -123.45, 12, -12, -42, -3.50, 123199, -12},
+		}, {
+			name:     "Positive",
+			val:      "  1,234.56  " + "$***123.45 " + "  12" + "+   12" + "   42 " + "    $3.50" + "12/31/99" + "  12  ",
+			expected: edited{1234.56, 123.45, 12, 12, 42, 3.50, 123199, 12},
+		}, {
+			name:     "Zero",
+			val:      "        0.00" + "*******.** " + "   0" + "+    0" + "    0 " + "    $0.00" + "00/00/00" + "     0",
+			expected: edited{},
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			got := edited{}
+			require.NoError(t, Unmarshal([]byte(tt.val), &got))
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestUnmarshal_NotEdited(t *testing.T) {
+	type plain struct {
+		I int     `pic:"5"`
+		U uint    `pic:"5"`
+		F float64 `pic:"6"`
+	}
+
+	c, err := Compile(reflect.TypeOf(plain{}))
+	require.NoError(t, err)
+
+	for _, val := range []string{
+		"1 2  " + "    1" + "     1",
+		"    1" + "12 34" + "     1",
+		"    1" + "    1" + "1,2.50",
+		"    1" + "$1,2 " + "     1",
+		"0012-" + "    1" + "     1",
+	} {
+		require.Error(t, Unmarshal([]byte(val), &plain{}), val)
+		require.Error(t, c.DecodeBytes([]byte(val), &plain{}), val)
+	}
+
+	got := plain{}
+	require.NoError(t, Unmarshal([]byte("  -12   34  1.50"), &got))
+	require.Equal(t, plain{-12, 34, 1.5}, got)
+}
+
+func TestUnmarshal_SeparateSign(t *testing.T) {
+	type signed struct {
+		Leading  int     `pic:"5,edited"` // PIC S9(4) SIGN LEADING SEPARATE
+		Trailing int     `pic:"5,edited"` // PIC S9(4) SIGN TRAILING SEPARATE
+		Decimal  float64 `pic:"8,edited"` // PIC S9(4).99 SIGN LEADING SEPARATE
+	}
+
+	got := signed{}
+	require.NoError(t, Unmarshal([]byte("-00120012-+0012.50"), &got))
+	require.Equal(t, signed{-12, -12, 12.5}, got)
+
+	require.NoError(t, Unmarshal([]byte("+00120012+-0012.50"), &got))
+	require.Equal(t, signed{12, 12, -12.5}, got)
+}
@@ -3,6 +3,7 @@ package pic
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // UnmarshalTypeError represents an unmarshal malfunction
@@ -29,3 +30,74 @@ func (e *UnmarshalTypeError) Error() string {
 
 	return err.Error()
 }
+
+// InvalidUnmarshalError represents a target to decode into that is not a
+// non-nil pointer
+type InvalidUnmarshalError struct {
+	Type reflect.Type // type of the target, nil if the target is nil
+}
+
+// Error converts details of an InvalidUnmarshalError into a meaningful string
+func (e *InvalidUnmarshalError) Error() string {
+	return "decode: unmarshal target object is not a pointer, or is nil"
+}
+
+// ValidationError represents a decoded field whose value is not one of the
+// values allowed by its values tag
+type ValidationError struct {
+	Value   string   // raw value
+	Allowed []string // values permitted by the field's values tag
+	Struct  string   // name of the struct type containing the field
+	Field   string   // name of the field holding the Go value
+}
+
+// Error converts details of a ValidationError into a meaningful string
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("pic: value %q of Go struct field %s.%s is not one of the allowed values [%s]",
+		e.Value, e.Struct, e.Field, strings.Join(e.Allowed, ","))
+}
+
+// ValidationErrors holds every ValidationError found while decoding a record
+type ValidationErrors []*ValidationError
+
+// Error converts each ValidationError into a single, meaningful string
+func (e ValidationErrors) Error() string {
+	ss := make([]string, len(e))
+	for i, err := range e {
+		ss[i] = err.Error()
+	}
+
+	return strings.Join(ss, "; ")
+}
+
+// TagError represents a struct field whose pic tags don't describe a value
+// that can be decoded into its Go type
+type TagError struct {
+	Type   reflect.Type // type of the field
+	Struct string       // name of the struct type containing the field
+	Field  string       // name of the field
+	Cause  error        // what is wrong with the field
+}
+
+// Error converts details of a TagError into a meaningful string
+func (e *TagError) Error() string {
+	return fmt.Sprintf("pic: invalid Go struct field %s.%s of type %s: %s", e.Struct, e.Field, e.Type, e.Cause)
+}
+
+// Unwrap returns the cause of a TagError
+func (e *TagError) Unwrap() error {
+	return e.Cause
+}
+
+// TagErrors holds every TagError found while validating a struct
+type TagErrors []*TagError
+
+// Error converts each TagError into a single, meaningful string
+func (e TagErrors) Error() string {
+	ss := make([]string, len(e))
+	for i, err := range e {
+		ss[i] = err.Error()
+	}
+
+	return strings.Join(ss, "; ")
+}
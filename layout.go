@@ -0,0 +1,150 @@
+package pic
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Layout describes where each field of a pic tagged struct is found within a
+// record.
+type Layout struct {
+	Type   reflect.Type // the struct type described
+	Length int          // total length of a record
+	Fields []Field      // every field, groups before their members
+}
+
+// Field describes a single field of a Layout. Groups (nested structs) and
+// OCCURS fields (slices) are listed before their members and occurrences.
+type Field struct {
+	Path      string       // field names from the root, e.g. Group.Items[2].Name
+	Depth     int          // nesting depth, 0 for fields of the root struct
+	Offset    int          // 0-based offset of the field within the record
+	Length    int          // length of the field, including every occurrence
+	Occurs    int          // occurs count, 0 if the field is not an OCCURS field
+	Type      reflect.Type // Go type of the field
+	Skip      bool         // field is never decoded, e.g. a FILLER
+	Values    []string     // allowed values, from the values tag
+	Redefines string       // path of the field whose bytes this field redefines
+	Tag       reflect.StructTag
+}
+
+// End returns the 0-based offset of the first byte after the field.
+func (f Field) End() int {
+	return f.Offset + f.Length
+}
+
+// LayoutOf describes the layout of v, which may be a pic tagged struct, a
+// pointer to one, or its reflect.Type.
+func LayoutOf(v interface{}) (*Layout, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("pic: cannot describe layout of non-struct type %v", t)
+	}
+
+	fields, err := appendFields(nil, t, "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Layout{
+		Type:   t,
+		Length: cachedStructRepresentation(t).len,
+		Fields: fields,
+	}, nil
+}
+
+// appendFields appends the fields of the struct type t to fields, or returns
+// a TagError for the first field whose tags are invalid, as Compile does.
+func appendFields(fields []Field, t reflect.Type, prefix string, offset, depth int) ([]Field, error) {
+	spec := cachedStructRepresentation(t)
+	for i, ff := range spec.fields {
+		sf := t.Field(i)
+		if ff.err != nil {
+			return nil, &TagError{sf.Type, t.Name(), sf.Name, ff.err}
+		}
+
+		var err error
+		if ff.inline {
+			// embedded fields are listed as if declared in t, like promoted fields
+			if fields, err = appendFields(fields, sf.Type, prefix, offset+ff.start-1, depth); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		f := Field{
+			Path:   prefix + sf.Name,
+			Depth:  depth,
+			Offset: offset + ff.start - 1,
+			Length: ff.len,
+			Occurs: ff.occurs,
+			Type:   sf.Type,
+			Skip:   ff.skip,
+			Values: ff.values,
+			Tag:    sf.Tag,
+		}
+
+		if ff.redefines >= 0 {
+			f.Redefines = prefix + t.Field(ff.redefines).Name
+		}
+
+		fields = append(fields, f)
+
+		if f.Skip {
+			continue
+		}
+
+		switch {
+		case f.Occurs > 0 && sf.Type.Kind() == reflect.Slice:
+			fields, err = appendOccurrences(fields, f)
+
+		case isGroup(sf.Type):
+			fields, err = appendFields(fields, sf.Type, f.Path+".", f.Offset, depth+1)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+func appendOccurrences(fields []Field, f Field) ([]Field, error) {
+	elem := f.Type.Elem()
+	size := f.Length / f.Occurs
+	for i := 0; i < f.Occurs; i++ {
+		o := Field{
+			Path:   f.Path + "[" + strconv.Itoa(i) + "]",
+			Depth:  f.Depth + 1,
+			Offset: f.Offset + i*size,
+			Length: size,
+			Type:   elem,
+		}
+		fields = append(fields, o)
+
+		if isGroup(elem) {
+			var err error
+			if fields, err = appendFields(fields, elem, o.Path+".", o.Offset, o.Depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// isGroup reports whether t is decoded as a group of pic tagged fields.
+func isGroup(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !isScanner(t)
+}
@@ -0,0 +1,109 @@
+package pic
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutOf(t *testing.T) {
+	type item struct {
+		Code string `pic:"2"`
+		Qty  int    `pic:"3"`
+	}
+
+	type group struct {
+		Name  string `pic:"4"`
+		Items []item `pic:"5,2"`
+	}
+
+	type record struct {
+		ID     string `pic:"6"`
+		_      string `pic:"2"`
+		Status string `pic:"1" values:"A,C"`
+		Group  group  `pic:"14"`
+		Tail   []int  `pic:"1,3"`
+	}
+
+	str, integer := reflect.TypeOf(""), reflect.TypeOf(0)
+	want := &Layout{
+		Type:   reflect.TypeOf(record{}),
+		Length: 26,
+		Fields: []Field{
+			{Path: "ID", Offset: 0, Length: 6, Type: str, Tag: `pic:"6"`},
+			{Path: "_", Offset: 6, Length: 2, Type: str, Skip: true, Tag: `pic:"2"`},
+			{Path: "Status", Offset: 8, Length: 1, Type: str, Values: []string{"A", "C"}, Tag: `pic:"1" values:"A,C"`},
+			{Path: "Group", Offset: 9, Length: 14, Type: reflect.TypeOf(group{}), Tag: `pic:"14"`},
+			{Path: "Group.Name", Depth: 1, Offset: 9, Length: 4, Type: str, Tag: `pic:"4"`},
+			{Path: "Group.Items", Depth: 1, Offset: 13, Length: 10, Occurs: 2, Type: reflect.TypeOf([]item{}), Tag: `pic:"5,2"`},
+			{Path: "Group.Items[0]", Depth: 2, Offset: 13, Length: 5, Type: reflect.TypeOf(item{})},
+			{Path: "Group.Items[0].Code", Depth: 3, Offset: 13, Length: 2, Type: str, Tag: `pic:"2"`},
+			{Path: "Group.Items[0].Qty", Depth: 3, Offset: 15, Length: 3, Type: integer, Tag: `pic:"3"`},
+			{Path: "Group.Items[1]", Depth: 2, Offset: 18, Length: 5, Type: reflect.TypeOf(item{})},
+			{Path: "Group.Items[1].Code", Depth: 3, Offset: 18, Length: 2, Type: str, Tag: `pic:"2"`},
+			{Path: "Group.Items[1].Qty", Depth: 3, Offset: 20, Length: 3, Type: integer, Tag: `pic:"3"`},
+			{Path: "Tail", Offset: 23, Length: 3, Occurs: 3, Type: reflect.TypeOf([]int{}), Tag: `pic:"1,3"`},
+			{Path: "Tail[0]", Depth: 1, Offset: 23, Length: 1, Type: integer},
+			{Path: "Tail[1]", Depth: 1, Offset: 24, Length: 1, Type: integer},
+			{Path: "Tail[2]", Depth: 1, Offset: 25, Length: 1, Type: integer},
+		},
+	}
+
+	for _, v := range []interface{}{record{}, &record{}, reflect.TypeOf(record{})} {
+		got, err := LayoutOf(v)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	require.Equal(t, 23, want.Fields[3].End())
+
+	type redefined struct {
+		Date string `pic:"8"`
+		Year int    `pic:"4" redefines:"Date"`
+		Day  int    `pic:"2" offset:"6" redefines:"Date"`
+	}
+
+	l, err := LayoutOf(redefined{})
+	require.NoError(t, err)
+	require.Equal(t, 8, l.Length)
+	require.Equal(t, Field{Path: "Day", Offset: 6, Length: 2, Type: integer, Redefines: "Date",
+		Tag: `pic:"2" offset:"6" redefines:"Date"`}, l.Fields[2])
+
+	_, err = LayoutOf("")
+	require.EqualError(t, err, "pic: cannot describe layout of non-struct type string")
+
+	_, err = LayoutOf(nil)
+	require.EqualError(t, err, "pic: cannot describe layout of non-struct type <nil>")
+}
+
+func TestLayoutOf_TagErrors(t *testing.T) {
+	type nested struct {
+		A string `pic:"x"`
+	}
+
+	for _, v := range []interface{}{
+		struct {
+			A string `pic:"x"`
+		}{},
+		struct {
+			A string `pic:"4"`
+			B string `pic:"2" offset:"2"`
+		}{},
+		struct {
+			A string `pic:"2"`
+			N nested `pic:"1"`
+		}{},
+	} {
+		_, want := Compile(reflect.TypeOf(v))
+		require.Error(t, want)
+
+		got, err := LayoutOf(v)
+		require.Nil(t, got)
+		require.Equal(t, want, err)
+
+		var te *TagError
+		require.True(t, errors.As(err, &te))
+	}
+}
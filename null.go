@@ -0,0 +1,114 @@
+package pic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NullPolicy describes which sentinel values mark a field as absent. Absent
+// fields decode to nil for pointers, to an invalid value for sql.Null* types
+// (or anything else implementing sql.Scanner), and to the zero value
+// otherwise.
+type NullPolicy uint8
+
+const (
+	// NullNone treats no value as absent.
+	NullNone NullPolicy = 0
+	// NullSpaces treats fields of only spaces as absent.
+	NullSpaces NullPolicy = 1 << iota
+	// NullLowValues treats fields of only LOW-VALUES (0x00) as absent.
+	NullLowValues
+	// NullHighValues treats fields of only HIGH-VALUES (0xFF) as absent.
+	NullHighValues
+
+	lowValue  = 0x00
+	highValue = 0xFF
+)
+
+var nullPolicyNames = map[string]NullPolicy{
+	"none":        NullNone,
+	"spaces":      NullSpaces,
+	"low-values":  NullLowValues,
+	"high-values": NullHighValues,
+}
+
+// WithNullPolicy sets the sentinel values that mark fields as absent for the
+// decoder. Fields may override it with a null tag, e.g.
+// `pic:"5" null:"low-values,high-values"`. By default only spaces are treated
+// as absent.
+func WithNullPolicy(p NullPolicy) Option {
+	return func(d *decoder) {
+		d.null = p
+	}
+}
+
+// parseNullPolicy parses a null tag, a comma separated list of none, spaces,
+// low-values and high-values.
+func parseNullPolicy(tag string) (NullPolicy, error) {
+	var p NullPolicy
+	for _, name := range strings.Split(tag, ",") {
+		np, ok := nullPolicyNames[strings.TrimSpace(name)]
+		if !ok {
+			return NullNone, fmt.Errorf("unknown null sentinel %q", name)
+		}
+		p |= np
+	}
+
+	return p, nil
+}
+
+// isNull reports whether s, a value with surrounding spaces already trimmed,
+// is absent according to the policy.
+func (p NullPolicy) isNull(s string) bool {
+	if len(s) == 0 {
+		return p&NullSpaces != 0
+	}
+
+	return (p&NullLowValues != 0 && repeats(s, lowValue)) ||
+		(p&NullHighValues != 0 && repeats(s, highValue))
+}
+
+// isNullBytes is isNull for bytes, so that a Codec can check fields without
+// allocating.
+func (p NullPolicy) isNullBytes(b []byte) bool {
+	if len(b) == 0 {
+		return p&NullSpaces != 0
+	}
+
+	return (p&NullLowValues != 0 && repeatsBytes(b, lowValue)) ||
+		(p&NullHighValues != 0 && repeatsBytes(b, highValue))
+}
+
+func repeatsBytes(b []byte, c byte) bool {
+	for _, x := range b {
+		if x != c {
+			return false
+		}
+	}
+
+	return true
+}
+
+// repeats reports whether every byte of s is b.
+func repeats(s string, b byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nullSetFunc wraps a setFunc, so that absent values are decoded to the zero
+// value of the field rather than being parsed.
+func nullSetFunc(set setFunc) setFunc {
+	return func(d *decoder, v reflect.Value, s string) error {
+		if d.null.isNull(s) {
+			return nilSetFunc(d, v, s)
+		}
+
+		return set(d, v, s)
+	}
+}
@@ -0,0 +1,15 @@
+// picvet checks the pic struct tags of Go packages, standalone or through
+// go vet, e.g.
+//
+//	go vet -vettool=$(which picvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/foundatn-io/go-pic/pkg/analysis/pictag"
+)
+
+func main() {
+	singlechecker.Main(pictag.Analyzer)
+}
@@ -0,0 +1,294 @@
+// Package pictag defines an Analyzer that checks the pic struct tags used by
+// github.com/foundatn-io/go-pic against the types of their fields, so that
+// layout mistakes are caught at build time rather than while decoding.
+package pictag
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	pic "github.com/foundatn-io/go-pic"
+)
+
+// Doc describes the Analyzer
+const Doc = `check pic struct tags against the types of their fields
+
+Reports pic, offset and null tags that can't be parsed, redefines and select
+tags naming fields that aren't declared before them, fields that overlap
+without redefining, untagged embedded struct pointers, select tags on fields
+that aren't interfaces, occurs counts on fields that aren't slices, slices
+without an occurs count, unexported fields, integer fields with more digits
+than their type can hold, and fields of kinds that can't be decoded.`
+
+// Analyzer checks pic struct tags
+var Analyzer = &analysis.Analyzer{
+	Name:             "pictag",
+	Doc:              Doc,
+	Requires:         []*analysis.Analyzer{inspect.Analyzer},
+	RunDespiteErrors: true,
+	Run:              run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st, ok := pass.TypesInfo.Types[n.(*ast.StructType)].Type.(*types.Struct)
+		if !ok {
+			return
+		}
+
+		spans, _ := layout(st)
+		for i := 0; i < st.NumFields(); i++ {
+			checkField(pass, st, spans, i)
+		}
+	})
+
+	return nil, nil
+}
+
+func checkField(pass *analysis.Pass, st *types.Struct, spans []span, i int) {
+	f, tag := st.Field(i), reflect.StructTag(st.Tag(i))
+	if _, ok := tag.Lookup("pic"); !ok {
+		if p, ok := f.Type().Underlying().(*types.Pointer); ok && f.Anonymous() && hasPicTags(p.Elem()) {
+			pass.Reportf(f.Pos(), "embedded %s must have a pic tag, or be embedded by value to be laid out in place", f.Type())
+		}
+
+		return
+	}
+
+	t, err := pic.ParseTag(tag)
+	if err != nil {
+		pass.Reportf(f.Pos(), "invalid pic tag on field %s: %v", f.Name(), err)
+		return
+	}
+
+	if t.Redefines != "" && !declaredBefore(st, i, t.Redefines) {
+		pass.Reportf(f.Pos(), "field %s redefines %s, which is not declared before it", f.Name(), t.Redefines)
+	}
+
+	if j := overlapped(spans, i); j >= 0 {
+		s, o := spans[i], spans[j]
+		pass.Reportf(f.Pos(), "field %s (%d-%d) overlaps field %s (%d-%d) without redefining it",
+			f.Name(), s.start-1, s.end, st.Field(j).Name(), o.start-1, o.end)
+	}
+
+	if t.Skip || f.Name() == "_" {
+		return
+	}
+
+	if !f.Exported() {
+		pass.Reportf(f.Pos(), "unexported field %s can't be decoded into", f.Name())
+		return
+	}
+
+	typ := f.Type()
+	if t.Select != "" {
+		if !types.IsInterface(typ) {
+			pass.Reportf(f.Pos(), "field %s of non-interface type %s has a select tag", f.Name(), typ)
+		} else if !declaredBefore(st, i, t.Select) {
+			pass.Reportf(f.Pos(), "field %s selects on %s, which is not declared before it", f.Name(), t.Select)
+		}
+
+		return
+	}
+
+	_, isSlice := typ.Underlying().(*types.Slice)
+	switch {
+	case t.Occurs > 0 && !isSlice:
+		pass.Reportf(f.Pos(), "field %s of non-slice type %s has an occurs count", f.Name(), typ)
+		return
+
+	case isSlice && t.Occurs == 0:
+		pass.Reportf(f.Pos(), "slice field %s has no occurs count", f.Name())
+		return
+
+	case isSlice:
+		typ = typ.Underlying().(*types.Slice).Elem()
+		t.Length /= t.Occurs
+	}
+
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+
+	if msg := checkType(pass, typ, t.Length); msg != "" {
+		pass.Reportf(f.Pos(), "field %s %s", f.Name(), msg)
+	}
+}
+
+// span is the bytes that a field takes in its struct, as the decoder lays it
+// out.
+type span struct {
+	start, end int // 1-based and inclusive, so end is start-1 if it takes none
+	redefines  int // index of the field whose bytes it redefines, or -1
+}
+
+// layout lays out the fields of st as the decoder does, returning the span of
+// each and the length of the struct. Fields without a pic tag, other than
+// embedded structs, and fields with invalid tags take no bytes.
+func layout(st *types.Struct) ([]span, int) {
+	spans := make([]span, st.NumFields())
+	last, length := 0, 0
+	for i := range spans {
+		f, tag := st.Field(i), reflect.StructTag(st.Tag(i))
+		spans[i] = span{start: last + 1, end: last, redefines: -1}
+
+		t, err := pic.ParseTag(tag)
+		if _, tagged := tag.Lookup("pic"); !tagged {
+			es, ok := f.Type().Underlying().(*types.Struct)
+			if !f.Anonymous() || !ok || isScanner(f.Type()) {
+				continue
+			}
+
+			// untagged embedded structs are laid out in place
+			_, l := layout(es)
+			t, err = pic.Tag{Length: l, Offset: -1}, nil
+		}
+
+		if err != nil {
+			continue
+		}
+
+		start := last
+		if t.Redefines != "" {
+			for j := 0; j < i; j++ {
+				if st.Field(j).Name() == t.Redefines {
+					spans[i].redefines = j
+					start = spans[j].start - 1
+					break
+				}
+			}
+		}
+
+		if t.Offset >= 0 {
+			start = t.Offset
+		}
+
+		spans[i].start, spans[i].end = start+1, start+t.Length
+
+		// a redefinition never moves the following fields back
+		if spans[i].redefines < 0 || spans[i].end > last {
+			last = spans[i].end
+		}
+
+		if spans[i].end > length {
+			length = spans[i].end
+		}
+	}
+
+	return spans, length
+}
+
+// overlapped returns the index of a field declared before field i that it
+// overlaps without either redefining the other, or both redefining the same
+// field, or -1 if there's none.
+func overlapped(spans []span, i int) int {
+	s := spans[i]
+	for j, o := range spans[:i] {
+		if s.end < s.start || o.end < o.start || s.start > o.end || o.start > s.end {
+			continue
+		}
+
+		if redefined(spans, i) != redefined(spans, j) {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// redefined returns the index of the field whose bytes field i takes,
+// following redefinitions of redefinitions.
+func redefined(spans []span, i int) int {
+	for spans[i].redefines >= 0 {
+		i = spans[i].redefines
+	}
+
+	return i
+}
+
+// checkType checks that a field of type t can hold length bytes of data,
+// returning what's wrong with it otherwise.
+func checkType(pass *analysis.Pass, t types.Type, length int) string {
+	if isScanner(t) {
+		return ""
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct, *types.Interface:
+		return ""
+
+	case *types.Basic:
+		info := u.Info()
+		switch {
+		case info&types.IsString != 0, info&types.IsFloat != 0:
+			return ""
+
+		case info&types.IsInteger != 0 && u.Kind() != types.Uintptr:
+			bits := uint(pass.TypesSizes.Sizeof(u) * 8)
+			max := uint64(1<<63-1) >> (64 - bits)
+			if info&types.IsUnsigned != 0 {
+				max = uint64(1<<64-1) >> (64 - bits)
+			}
+
+			if digits := len(strconv.FormatUint(max, 10)) - 1; length > digits {
+				return fmt.Sprintf("of type %s cannot hold %d digits, at most %d", t, length, digits)
+			}
+
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("has unsupported type %s", t)
+}
+
+// hasPicTags reports whether t is a struct with pic tagged fields, itself or in
+// the structs embedded in it by value, so is laid out as part of a record.
+func hasPicTags(t types.Type) bool {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok || isScanner(t) {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if _, ok := reflect.StructTag(st.Tag(i)).Lookup("pic"); ok {
+			return true
+		}
+
+		if f := st.Field(i); f.Anonymous() && hasPicTags(f.Type()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isScanner reports whether a pointer to t implements sql.Scanner, going by
+// its Scan method.
+func isScanner(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Scan")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 1 && sig.Results().Len() == 1
+}
+
+func declaredBefore(st *types.Struct, i int, name string) bool {
+	for j := 0; j < i; j++ {
+		if st.Field(j).Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
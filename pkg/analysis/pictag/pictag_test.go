@@ -0,0 +1,13 @@
+package pictag_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/foundatn-io/go-pic/pkg/analysis/pictag"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), pictag.Analyzer, "a")
+}
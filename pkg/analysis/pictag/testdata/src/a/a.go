@@ -0,0 +1,71 @@
+package a
+
+import "database/sql"
+
+type group struct {
+	Name string `pic:"4"`
+}
+
+type valid struct {
+	Name    string        `pic:"10"`
+	Small   int8          `pic:"2"`
+	Big     uint64        `pic:"19"`
+	Amount  float64       `pic:"12,skip"`
+	Null    sql.NullInt64 `pic:"5" null:"spaces,low-values"`
+	Ptr     *int32        `pic:"9"`
+	Codes   []uint16      `pic:"4,2"`
+	Groups  []group       `pic:"4,3"`
+	Group   group         `pic:"4" offset:"80"`
+	Again   string        `pic:"4" redefines:"Group"`
+	Any     interface{}   `pic:"4"`
+	Helper  map[string]int
+	_       bool     `pic:"1"`
+	Skipped chan int `pic:"3,skip"`
+}
+
+type invalid struct {
+	Tag      string      `pic:"x"`                   // want `invalid pic tag on field Tag: failed string->int conversion`
+	Null     string      `pic:"1" null:"?"`          // want `invalid pic tag on field Null: unknown null sentinel "\?"`
+	Offset   string      `pic:"1" offset:"-2"`       // want `invalid pic tag on field Offset: negative offset -2`
+	Small    int8        `pic:"3"`                   // want `field Small of type int8 cannot hold 3 digits, at most 2`
+	Word     uint16      `pic:"5"`                   // want `field Word of type uint16 cannot hold 5 digits, at most 4`
+	Elems    []int16     `pic:"5,2"`                 // want `field Elems of type int16 cannot hold 5 digits, at most 4`
+	Codes    []int       `pic:"4"`                   // want `slice field Codes has no occurs count`
+	Occurs   string      `pic:"4,2"`                 // want `field Occurs of non-slice type string has an occurs count`
+	Map      map[int]int `pic:"2"`                   // want `field Map has unsupported type map\[int\]int`
+	Flag     bool        `pic:"1"`                   // want `field Flag has unsupported type bool`
+	Redefine string      `pic:"1" redefines:"Later"` // want `field Redefine redefines Later, which is not declared before it`
+	Later    string      `pic:"1"`
+	Selected string      `pic:"1" select:"Later"` // want `field Selected of non-interface type string has a select tag`
+	Variant  interface{} `pic:"1" select:"Kind"`  // want `field Variant selects on Kind, which is not declared before it`
+	hidden   string      `pic:"1"`                // want `unexported field hidden can't be decoded into`
+	Overlap  string      `pic:"4" offset:"2"`     // want `field Overlap \(2-6\) overlaps field Small \(0-3\) without redefining it`
+}
+
+type header struct {
+	Kind string `pic:"2"`
+}
+
+type overlaps struct {
+	header
+	Name    string `pic:"6"`
+	Alias   string `pic:"6" redefines:"Name"`
+	Short   string `pic:"2" redefines:"Alias"`
+	Rest    string `pic:"4" offset:"4"` // want `field Rest \(4-8\) overlaps field Name \(2-8\) without redefining it`
+	Skipped string `pic:"2,skip"`
+	Code    string `pic:"2" offset:"1"` // want `field Code \(1-3\) overlaps field header \(0-2\) without redefining it`
+	After   string `pic:"2" offset:"10"`
+}
+
+type pointers struct {
+	*header // want `embedded \*a.header must have a pic tag, or be embedded by value to be laid out in place`
+	*sql.NullString
+	Amount int `pic:"3"`
+}
+
+type body interface{}
+
+type selected struct {
+	Kind string `pic:"1"`
+	Body body   `pic:"9" select:"Kind"`
+}
@@ -0,0 +1,619 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// pseudoTextDelimiter delimits the pseudo-text operands of a REPLACING phrase,
+// e.g. ==:PREFIX:==.
+const pseudoTextDelimiter = "=="
+
+// copyExtensions are the extensions that the file of a member named by a COPY
+// statement may have, tried in turn.
+var copyExtensions = []string{"", ".cpy", ".CPY", ".cbl", ".CBL", ".cob", ".COB", ".txt"}
+
+// library is a directory of an fs.FS that the members named by COPY
+// statements are found in.
+type library struct {
+	fsys fs.FS
+	dir  string
+}
+
+// member is a copybook read for the lexer: the one given to New, or one
+// copied into it by a COPY statement.
+type member struct {
+	name   string // name given in errors
+	key    string // identifies the member, to detect COPY cycles
+	source string // the copybook as given
+	text   string // the copybook as read by the preprocessor
+}
+
+// span is a run of the lexer's input that comes from a member.
+type span struct {
+	start    Pos     // where the span starts in the input
+	m        *member // the member it comes from
+	off      Pos     // where the span starts in the member's text
+	replaced bool    // the span is text put in place of that at off by REPLACING
+}
+
+// position is where an item was found in the source.
+type position struct {
+	name   string // the copybook it's in
+	line   int    // counting from 1
+	column int    // counting from 1
+	text   string // the line, as given in the source
+}
+
+// locate returns the position of p in the member's text.
+func (m *member) locate(p Pos) position {
+	if int(p) > len(m.text) {
+		p = Pos(len(m.text))
+	}
+
+	start := strings.LastIndexByte(m.text[:p], '\n') + 1
+	n := strings.Count(m.text[:p], "\n")
+	at := position{name: m.name, line: n + 1, column: int(p) - start + 1}
+	if lines := strings.Split(m.source, "\n"); n < len(lines) {
+		at.text = strings.TrimRight(lines[n], "\r")
+	}
+
+	return at
+}
+
+// partial says which part of a text word a REPLACING operand is found as.
+type partial int
+
+const (
+	wholeWords   partial = iota // one or more whole text words
+	leadingPart                 // the start of a word, after LEADING
+	trailingPart                // the end of a word, after TRAILING
+	tagPart                     // a tag between colons, e.g. :PREFIX:, anywhere in a word
+)
+
+// replacing is an operand pair of a REPLACING phrase, by which the text words
+// of one are replaced by the text of the other wherever they're found.
+type replacing struct {
+	from []string // text words to find
+	to   string   // text to put in their place
+	part partial
+}
+
+// match returns how many of the text words at the start of words r replaces
+// as whole words, which is none if they don't match.
+func (r replacing) match(words []textWord) int {
+	if r.part != wholeWords || len(words) < len(r.from) {
+		return 0
+	}
+
+	for i, w := range r.from {
+		if !sameWord(words[i].val, w) {
+			return 0
+		}
+	}
+
+	return len(r.from)
+}
+
+// within returns the index of the first part of the text word w, at or after
+// from, that r replaces, or -1 if there's none.
+func (r replacing) within(w string, from int) int {
+	if r.part == wholeWords || isQuote(w[0]) {
+		return -1
+	}
+
+	u, f := strings.ToUpper(w), strings.ToUpper(r.from[0])
+	switch r.part {
+	case leadingPart:
+		if from == 0 && strings.HasPrefix(u, f) {
+			return 0
+		}
+
+	case trailingPart:
+		if strings.HasSuffix(u[from:], f) {
+			return len(u) - len(f)
+		}
+
+	case tagPart:
+		if i := strings.Index(u[from:], f); i >= 0 {
+			return from + i
+		}
+	}
+
+	return -1
+}
+
+// copyStatement is a COPY statement, of the form
+//
+//	COPY text-name [(OF | IN) library-name] [SUPPRESS]
+//	     [REPLACING {[LEADING | TRAILING] operand BY operand}] .
+//
+// in which an operand is pseudo-text, between == delimiters, or a single text
+// word.
+type copyStatement struct {
+	name       string // of the member to copy
+	literal    bool   // the name is a literal, so is the file name as it is
+	library    string // of the directory the member is in, if any
+	replacings []replacing
+	end        int // index of the text word after the statement
+}
+
+// copyParser parses a COPY statement from the text words of a member.
+type copyParser struct {
+	text  string
+	words []textWord
+	i     int // index of the next text word
+	st    *copyStatement
+}
+
+// parseCopy parses the COPY statement at the text word k of the member's text.
+func parseCopy(text string, words []textWord, k int) (*copyStatement, error) {
+	p := &copyParser{text: text, words: words, i: k + 1, st: &copyStatement{}}
+	if !p.atOperand() {
+		return nil, errors.New("expected the name of a copybook after COPY")
+	}
+
+	p.st.name, p.st.literal = p.name()
+	if p.keyword("OF", "IN") {
+		if !p.atOperand() {
+			return nil, errors.New("expected a library name after OF or IN")
+		}
+
+		p.st.library, _ = p.name()
+	}
+
+	p.keyword("SUPPRESS")
+	if p.keyword("REPLACING") {
+		if err := p.parseReplacings(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.keyword(".") {
+		return nil, errors.New("COPY statement is not terminated by a period")
+	}
+
+	p.st.end = p.i
+	return p.st, nil
+}
+
+// keyword moves past the next text word if it's one of the words, and reports
+// whether it was.
+func (p *copyParser) keyword(words ...string) bool {
+	if p.i >= len(p.words) {
+		return false
+	}
+
+	for _, w := range words {
+		if strings.EqualFold(p.words[p.i].val, w) {
+			p.i++
+			return true
+		}
+	}
+
+	return false
+}
+
+// atOperand reports whether the next text word may be an operand.
+func (p *copyParser) atOperand() bool {
+	if p.i >= len(p.words) {
+		return false
+	}
+
+	switch strings.ToUpper(p.words[p.i].val) {
+	case ".", "BY", "REPLACING":
+		return false
+	}
+
+	return true
+}
+
+// name returns the next text word as a name, unquoted if it's a literal, and
+// moves past it.
+func (p *copyParser) name() (string, bool) {
+	w := p.words[p.i].val
+	p.i++
+	if isQuote(w[0]) && len(w) > 1 {
+		return unquote(w), true
+	}
+
+	return w, false
+}
+
+func (p *copyParser) parseReplacings() error {
+	for p.atOperand() {
+		r := replacing{}
+		switch {
+		case p.keyword("LEADING"):
+			r.part = leadingPart
+		case p.keyword("TRAILING"):
+			r.part = trailingPart
+		}
+
+		from, _, err := p.parseOperand()
+		if err != nil {
+			return err
+		}
+
+		if !p.keyword("BY") {
+			return errors.New("expected BY in REPLACING phrase")
+		}
+
+		_, to, err := p.parseOperand()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(from) == 0:
+			return errors.New("pseudo-text to replace is empty")
+
+		case r.part != wholeWords && len(from) != 1:
+			return errors.New("LEADING or TRAILING pseudo-text must be one text word")
+
+		case r.part == wholeWords && len(from) == 1 && isTag(from[0]):
+			r.part = tagPart
+		}
+
+		r.from, r.to = from, strings.TrimSpace(to)
+		p.st.replacings = append(p.st.replacings, r)
+	}
+
+	if len(p.st.replacings) == 0 {
+		return errors.New("expected an operand after REPLACING")
+	}
+
+	return nil
+}
+
+// parseOperand parses pseudo-text, or a single text word, and returns its text
+// words, and its text.
+func (p *copyParser) parseOperand() ([]string, string, error) {
+	if !p.atOperand() {
+		return nil, "", errors.New("expected a REPLACING operand")
+	}
+
+	open := p.words[p.i]
+	p.i++
+	if open.val != pseudoTextDelimiter {
+		return []string{open.val}, open.val, nil
+	}
+
+	var words []string
+	for ; p.i < len(p.words); p.i++ {
+		if w := p.words[p.i]; w.val == pseudoTextDelimiter {
+			p.i++
+			return words, p.text[open.end:w.start], nil
+		}
+
+		words = append(words, p.words[p.i].val)
+	}
+
+	return nil, "", errors.New("pseudo-text is not terminated by ==")
+}
+
+// copier builds the lexer's input from a copybook, and the members its COPY
+// statements copy into it.
+type copier struct {
+	l     *lexer
+	b     strings.Builder
+	spans []span
+	stack []*member // the members being copied, outermost first
+}
+
+// expand sets the lexer's input to the text of the copybook m, with each
+// COPY statement in it replaced by the member it names. If a COPY statement
+// can't be expanded, the input ends before it, and the lexer ends with an
+// error there.
+func (l *lexer) expand(m *member) {
+	c := &copier{l: l, stack: []*member{m}}
+	err := c.copyMember(m, nil)
+	l.input, l.spans = c.b.String(), c.spans
+	if err != nil {
+		l.logf("%v", err)
+		l.copyErr = err
+	}
+}
+
+// locate returns the position of p in the source, in whichever member the
+// text at p comes from.
+func (l *lexer) locate(p Pos) position {
+	i := sort.Search(len(l.spans), func(i int) bool {
+		return l.spans[i].start > p
+	}) - 1
+
+	if i < 0 {
+		return (&member{name: l.name, source: l.input, text: l.input}).locate(p)
+	}
+
+	s := l.spans[i]
+	q := s.off
+	if !s.replaced {
+		q += p - s.start
+	}
+
+	return s.m.locate(q)
+}
+
+// write adds text from the member m, found at off in its text, to the input.
+func (c *copier) write(m *member, text string, off int, replaced bool) {
+	c.spans = append(c.spans, span{start: Pos(c.b.Len()), m: m, off: Pos(off), replaced: replaced})
+	c.b.WriteString(text)
+}
+
+// copyMember writes the text of the member m to the input, with the text that
+// the operand pairs reps replace replaced, and the members named by its COPY
+// statements copied in place of them.
+func (c *copier) copyMember(m *member, reps []replacing) error {
+	words := textWords(m.text)
+	last := 0 // offset of the text not yet written
+	for k := 0; k < len(words); {
+		w := words[k]
+		if strings.EqualFold(w.val, "COPY") {
+			c.write(m, m.text[last:w.start], last, false)
+			st, err := parseCopy(m.text, words, k)
+			if err != nil {
+				return err
+			}
+
+			if err := c.copyStatement(m, st); err != nil {
+				return err
+			}
+
+			last, k = words[st.end-1].end, st.end
+			continue
+		}
+
+		if r, n := matchAny(reps, words[k:]); n > 0 {
+			c.write(m, m.text[last:w.start], last, false)
+			c.write(m, r.to, w.start, true)
+			last, k = words[k+n-1].end, k+n
+			continue
+		}
+
+		last = c.replaceWithin(m, w, reps, last)
+		k++
+	}
+
+	c.write(m, m.text[last:], last, false)
+	return nil
+}
+
+// matchAny returns the first of the operand pairs reps that replaces the text
+// words at the start of words, and how many it replaces.
+func matchAny(reps []replacing, words []textWord) (replacing, int) {
+	for _, r := range reps {
+		if n := r.match(words); n > 0 {
+			return r, n
+		}
+	}
+
+	return replacing{}, 0
+}
+
+// replaceWithin writes the text before the text word w, from last, and the
+// parts of w that the operand pairs reps replace, replaced. It returns the
+// offset of the text not yet written.
+func (c *copier) replaceWithin(m *member, w textWord, reps []replacing, last int) int {
+	for from := 0; from < len(w.val); {
+		first, idx := -1, len(w.val)
+		for i, r := range reps {
+			if j := r.within(w.val, from); j >= 0 && j < idx {
+				first, idx = i, j
+			}
+		}
+
+		if first < 0 {
+			break
+		}
+
+		at := w.start + idx
+		c.write(m, m.text[last:at], last, false)
+		c.write(m, reps[first].to, at, true)
+		last = at + len(reps[first].from[0])
+		from = idx + len(reps[first].from[0])
+	}
+
+	return last
+}
+
+// copyStatement copies the member named by the COPY statement st, in the
+// member m, into the input.
+func (c *copier) copyStatement(m *member, st *copyStatement) error {
+	cm, err := c.find(st)
+	if err != nil {
+		return err
+	}
+
+	for i, o := range c.stack {
+		if o.key != cm.key {
+			continue
+		}
+
+		names := make([]string, 0, len(c.stack)-i+1)
+		for _, o := range c.stack[i:] {
+			names = append(names, o.name)
+		}
+
+		return fmt.Errorf("COPY %s is circular: %s", st.name, strings.Join(append(names, cm.name), " -> "))
+	}
+
+	c.l.logf("copying %s into %s", cm.name, m.name)
+	c.stack = append(c.stack, cm)
+	defer func() {
+		c.stack = c.stack[:len(c.stack)-1]
+	}()
+
+	if err := c.copyMember(cm, st.replacings); err != nil {
+		return err
+	}
+
+	// the text after the statement mustn't run on from the last line of the
+	// member, which may be a comment
+	if !strings.HasSuffix(cm.text, "\n") {
+		c.write(cm, "\n", len(cm.text), true)
+	}
+
+	return nil
+}
+
+// find reads the member that the COPY statement st names, from the first
+// library that has it.
+func (c *copier) find(st *copyStatement) (*member, error) {
+	libs := c.l.pre.libraries
+	if len(libs) == 0 {
+		return nil, fmt.Errorf("copybook %s not found: no library to find it in", st.name)
+	}
+
+	for i, lib := range libs {
+		for _, name := range candidates(st) {
+			p := path.Join(lib.dir, name)
+			b, err := fs.ReadFile(lib.fsys, p)
+			switch {
+			case err == nil:
+				src := string(b)
+				return &member{name: p, key: fmt.Sprintf("%d:%s", i, p), source: src, text: c.l.pre.read(src)}, nil
+
+			case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrInvalid):
+				continue
+
+			default:
+				return nil, fmt.Errorf("failed to read copybook %s: %w", p, err)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("copybook %s not found", st.name)
+}
+
+// candidates returns the paths, within a library, that the file of the member
+// named by the COPY statement st may have. A name given as a literal is the
+// path itself, but a member named by a word may be in a file of the name in
+// upper or lower case, with any of the copyExtensions.
+func candidates(st *copyStatement) []string {
+	if st.literal {
+		return []string{path.Join(st.library, st.name)}
+	}
+
+	var paths []string
+	for _, dir := range caseVariants(st.library) {
+		for _, name := range caseVariants(st.name) {
+			for _, ext := range copyExtensions {
+				paths = append(paths, path.Join(dir, name+ext))
+			}
+		}
+	}
+
+	return paths
+}
+
+// caseVariants returns s, and s in lower case, if that's any different.
+func caseVariants(s string) []string {
+	if lower := strings.ToLower(s); lower != s {
+		return []string{s, lower}
+	}
+
+	return []string{s}
+}
+
+// textWord is a word of text, as COPY statements and REPLACING see it: a
+// literal, a parenthesis, a separator period, the == that delimits
+// pseudo-text, or a run of any other characters up to one of those, or a
+// space.
+type textWord struct {
+	val        string
+	start, end int // offsets in the text
+}
+
+// textWords splits s into its text words, skipping spaces, separator commas
+// and semicolons, and *> comments.
+func textWords(s string) []textWord {
+	var words []textWord
+	for i := 0; i < len(s); {
+		switch c := rune(s[i]); {
+		case isSpace(c), isEOL(c), (c == ',' || c == ';') && atSpace(s, i+1):
+			i++
+
+		case strings.HasPrefix(s[i:], commentPrefix):
+			if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(s)
+			}
+
+		default:
+			j := i + wordLen(s[i:])
+			words = append(words, textWord{val: s[i:j], start: i, end: j})
+			i = j
+		}
+	}
+
+	return words
+}
+
+// wordLen returns the length of the text word at the start of s.
+func wordLen(s string) int {
+	switch c := s[0]; {
+	case isQuote(c):
+		for i := 1; i < len(s); i++ {
+			switch {
+			case s[i] == c && i+1 < len(s) && s[i+1] == c:
+				i++
+			case s[i] == c:
+				return i + 1
+			case isEOL(rune(s[i])):
+				return i
+			}
+		}
+
+		return len(s)
+
+	case strings.HasPrefix(s, pseudoTextDelimiter):
+		return len(pseudoTextDelimiter)
+
+	case c == leftParen, c == rightParen, c == '.' && atSpace(s, 1):
+		return 1
+	}
+
+	i := 1
+	for ; i < len(s); i++ {
+		c := rune(s[i])
+		if isSpace(c) || isEOL(c) || isQuote(s[i]) || c == leftParen || c == rightParen ||
+			isPunctuation(c) && atSpace(s, i+1) ||
+			strings.HasPrefix(s[i:], pseudoTextDelimiter) || strings.HasPrefix(s[i:], commentPrefix) {
+			break
+		}
+	}
+
+	return i
+}
+
+// atSpace reports whether s has a space or end of line at i, or ends there.
+func atSpace(s string, i int) bool {
+	return i >= len(s) || isSpace(rune(s[i])) || isEOL(rune(s[i]))
+}
+
+// isQuote reports whether c opens a literal.
+func isQuote(c byte) bool {
+	return rune(c) == singleQuote || rune(c) == doubleQuote
+}
+
+// isTag reports whether the text word w is a tag between colons, such as
+// :PREFIX:, which REPLACING finds anywhere within a word.
+func isTag(w string) bool {
+	return len(w) > 2 && strings.HasPrefix(w, ":") && strings.HasSuffix(w, ":")
+}
+
+// sameWord reports whether the text words a and b are the same: literals
+// exactly, and other words whatever their case.
+func sameWord(a, b string) bool {
+	if isQuote(a[0]) {
+		return a == b
+	}
+
+	return strings.EqualFold(a, b)
+}
@@ -0,0 +1,222 @@
+package lex
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLibrary holds the members named by the COPY statements under test.
+var testLibrary = fstest.MapFS{
+	"lib/ADDRESS.cpy": {Data: []byte(`
+       05  :PREFIX:-STREET         PIC X(20).
+       05  :PREFIX:-POSTCODE       PIC X(8).
+`)},
+	"lib/amount.cpy": {Data: []byte(`
+       05  AMOUNT                  PIC 9(5).
+       05  AMOUNT-SIGN             PIC X.`)},
+	"lib/NESTED": {Data: []byte(`
+       05  NESTED-ID               PIC 9(4).
+           COPY AMOUNT.
+`)},
+	"lib/CYCLE-A.cpy": {Data: []byte(`
+       05  CYCLE-A-ID              PIC X.
+           COPY CYCLE-B.
+`)},
+	"lib/CYCLE-B.cpy": {Data: []byte(`
+       05  CYCLE-B-ID              PIC X.
+           COPY CYCLE-A.
+`)},
+	"lib/BROKEN.cpy": {Data: []byte(`
+       05  BROKEN-ID               PIC X.
+       05  BROKEN-CODE             PIC X OCCURS SOME.
+`)},
+	"other/AMOUNT.cpy": {Data: []byte(`
+       05  OTHER-AMOUNT            PIC 9(3).
+`)},
+	"other/sub/CODES.cpy": {Data: []byte(`
+       05  CODE-1                  PIC X.
+`)},
+}
+
+func Test_Parse_Copy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		dirs  []string
+		want  *Record
+	}{
+		{
+			name: "Member",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT.
+       05  ORDER-ID                PIC X(3).
+`,
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 9, Children: []*Record{
+				{Name: "AMOUNT", Typ: reflect.Uint, Length: 5},
+				{Name: "AMOUNT-SIGN", Typ: reflect.String, Length: 1},
+				{Name: "ORDER-ID", Typ: reflect.String, Length: 3},
+			}},
+		}, {
+			name: "ReplacingTag",
+			input: `
+       01  CUSTOMER.
+           COPY ADDRESS REPLACING ==:PREFIX:== BY ==CUST==.
+           COPY ADDRESS REPLACING ==:PREFIX:== BY ==BILL==.
+`,
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 56, Children: []*Record{
+				{Name: "CUST-STREET", Typ: reflect.String, Length: 20},
+				{Name: "CUST-POSTCODE", Typ: reflect.String, Length: 8},
+				{Name: "BILL-STREET", Typ: reflect.String, Length: 20},
+				{Name: "BILL-POSTCODE", Typ: reflect.String, Length: 8},
+			}},
+		}, {
+			name: "ReplacingWordsAndLeading",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT REPLACING ==PIC 9(5)== BY ==PIC 9(7)==
+                                 LEADING ==AMOUNT== BY ==TOTAL==.
+`,
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 8, Children: []*Record{
+				{Name: "TOTAL", Typ: reflect.Uint, Length: 7},
+				{Name: "TOTAL-SIGN", Typ: reflect.String, Length: 1},
+			}},
+		}, {
+			name: "NestedAndOnOneLine",
+			input: `
+       01  ORDER-REC.  COPY NESTED.  05  ORDER-ID  PIC X(3).
+`,
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 13, Children: []*Record{
+				{Name: "NESTED-ID", Typ: reflect.Uint, Length: 4},
+				{Name: "AMOUNT", Typ: reflect.Uint, Length: 5},
+				{Name: "AMOUNT-SIGN", Typ: reflect.String, Length: 1},
+				{Name: "ORDER-ID", Typ: reflect.String, Length: 3},
+			}},
+		}, {
+			name: "SearchPathOrder",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT.
+           COPY CODES OF SUB.
+`,
+			dirs: []string{"other", "lib"},
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 4, Children: []*Record{
+				{Name: "OTHER-AMOUNT", Typ: reflect.Uint, Length: 3},
+				{Name: "CODE-1", Typ: reflect.String, Length: 1},
+			}},
+		}, {
+			name: "LiteralName",
+			input: `
+       01  ORDER-REC.
+           COPY 'sub/CODES.cpy' IN other.
+`,
+			dirs: []string{"."},
+			want: &Record{Name: "test", Typ: reflect.Struct, Length: 1, Children: []*Record{
+				{Name: "CODE-1", Typ: reflect.String, Length: 1},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dirs := tt.dirs
+			if dirs == nil {
+				dirs = []string{"lib"}
+			}
+
+			got, err := NewTree(New("test", tt.input, WithCopybooks(testLibrary, dirs...))).Parse()
+			require.NoError(t, err)
+			deepCompare(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Parse_CopyErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  *Error
+		err   string
+	}{
+		{
+			name: "NoLibrary",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT.
+`,
+			want: &Error{Name: "test", Line: 3, Column: 12, Text: "           COPY AMOUNT."},
+			err:  "copybook AMOUNT not found: no library to find it in",
+		}, {
+			name: "NotFound",
+			input: `
+       01  ORDER-REC.
+           COPY MISSING.
+`,
+			opts: []Option{WithCopybooks(testLibrary, "lib")},
+			want: &Error{Name: "test", Line: 3, Column: 12, Text: "           COPY MISSING."},
+			err:  "copybook MISSING not found",
+		}, {
+			name: "NotTerminated",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT
+`,
+			opts: []Option{WithCopybooks(testLibrary, "lib")},
+			want: &Error{Name: "test", Line: 3, Column: 12, Text: "           COPY AMOUNT"},
+			err:  "COPY statement is not terminated by a period",
+		}, {
+			name: "BadReplacing",
+			input: `
+       01  ORDER-REC.
+           COPY AMOUNT REPLACING ==AMOUNT== ==TOTAL==.
+`,
+			opts: []Option{WithCopybooks(testLibrary, "lib")},
+			want: &Error{Name: "test", Line: 3, Column: 12, Text: "           COPY AMOUNT REPLACING ==AMOUNT== ==TOTAL==."},
+			err:  "expected BY in REPLACING phrase",
+		}, {
+			name: "Cycle",
+			input: `
+       01  ORDER-REC.
+           COPY CYCLE-A.
+`,
+			opts: []Option{WithCopybooks(testLibrary, "lib")},
+			want: &Error{Name: "lib/CYCLE-B.cpy", Line: 3, Column: 12, Text: "           COPY CYCLE-A."},
+			err:  "COPY CYCLE-A is circular: lib/CYCLE-A.cpy -> lib/CYCLE-B.cpy -> lib/CYCLE-A.cpy",
+		}, {
+			name: "InMember",
+			input: `
+       01  ORDER-REC.
+           COPY BROKEN.
+`,
+			opts: []Option{WithCopybooks(testLibrary, "lib")},
+			want: &Error{Name: "lib/BROKEN.cpy", Line: 3, Column: 49, Text: "       05  BROKEN-CODE             PIC X OCCURS SOME."},
+			err:  `expected an integer, found "SOME"`,
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewTree(New("test", tt.input, tt.opts...)).Parse()
+			require.Nil(t, got)
+
+			var e *Error
+			require.True(t, errors.As(err, &e), "want an *Error, got %v", err)
+			require.Equal(t, tt.want.Name, e.Name)
+			require.Equal(t, tt.want.Line, e.Line)
+			require.Equal(t, tt.want.Column, e.Column)
+			require.Equal(t, tt.want.Text, e.Text)
+			require.EqualError(t, e.Err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package lex
+
+import (
+	"strings"
+)
+
+// entry is a data description entry, which runs from its level number to the
+// period that ends it, over as many lines as it takes, e.g.
+//
+//	15  DUMMY-SUBGROUP-2-OBJECT-A  PIC X(12)
+//	    OCCURS 12 TIMES.
+type entry struct {
+	items []item // items of the entry, without spaces, separators or its period
+	doc   string // text of the comment lines before the entry, if kept
+}
+
+// splitComment splits a comment at the end of a line from the items before
+// it, returning its text, if any, and the items without it or the spaces
+// before it.
+func splitComment(items []item) ([]item, string) {
+	if len(items) == 0 || items[len(items)-1].typ != itemComment {
+		return items, ""
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(items[len(items)-1].val, commentPrefix))
+	items = items[:len(items)-1]
+	for len(items) > 0 && items[len(items)-1].typ == itemSpace {
+		items = items[:len(items)-1]
+	}
+
+	// lines of asterisks only frame comments
+	if strings.Trim(text, "*") == "" {
+		text = ""
+	}
+
+	return items, text
+}
+
+// isBlank reports whether a line has nothing but spaces.
+func isBlank(items []item) bool {
+	for _, i := range items {
+		if i.typ != itemSpace {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listingDirectives are the compiler-directing statements that only direct
+// the compiler's listing, e.g. EJECT or TITLE 'ACCOUNTS', which may be found
+// on lines of their own between entries.
+var listingDirectives = map[string]struct{}{
+	"EJECT": {}, "SKIP1": {}, "SKIP2": {}, "SKIP3": {}, "TITLE": {},
+}
+
+// isDirective reports whether a line is a compiler-directing statement that
+// only directs the listing.
+func isDirective(items []item) bool {
+	for _, i := range items {
+		if isSeparator(i) {
+			continue
+		}
+
+		_, ok := listingDirectives[strings.ToUpper(i.val)]
+		return i.typ == itemKeyword && ok
+	}
+
+	return false
+}
+
+// isSeparator reports whether the item i only separates the words of an
+// entry, as spaces, commas and semicolons do.
+func isSeparator(i item) bool {
+	return i.typ == itemSpace || i.typ == itemChar && (i.val == "," || i.val == ";")
+}
@@ -0,0 +1,34 @@
+package lex
+
+import (
+	"fmt"
+)
+
+// Error describes where, and why, a copybook could not be parsed.
+type Error struct {
+	Name   string // name of the copybook, or of the member copied into it
+	Line   int    // line of the offending text, counting from 1
+	Column int    // column of the offending text, counting from 1
+	Text   string // source line holding the offending text
+	Err    error  // what is wrong with it
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v: %q", e.Name, e.Line, e.Column, e.Err, e.Text)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// errorFor returns an Error for the item it, in whichever copybook it was
+// found.
+func (t *Tree) errorFor(it item, err error) error {
+	e := &Error{Name: t.name, Line: it.line, Column: 1, Err: err}
+	if t.lex != nil {
+		at := t.lex.locate(it.pos)
+		e.Name, e.Line, e.Column, e.Text = at.name, at.line, at.column, at.text
+	}
+
+	return e
+}
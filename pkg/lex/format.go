@@ -0,0 +1,320 @@
+package lex
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Columns of a line of source in COBOL reference format:
+//
+//	1-6    sequence area
+//	7      indicator area
+//	8-11   Area A
+//	12-72  Area B
+//	73-80  identification area
+const (
+	indicatorColumn = 7
+	areaBEndColumn  = 72
+)
+
+// Indicators that may appear in the indicator area (column 7).
+const (
+	commentIndicator      = '*'
+	pageEjectIndicator    = '/'
+	debuggingIndicator    = 'D'
+	continuationIndicator = '-'
+)
+
+// commentPrefix starts a comment that runs to the end of its line. Comment
+// lines that are kept are passed to the lexer as such comments.
+const commentPrefix = "*>"
+
+// directivePrefix starts a compiler directive, such as >>SOURCE FORMAT FREE.
+const directivePrefix = ">>"
+
+// Format is the source format of a copybook.
+type Format int
+
+const (
+	// AutoFormat reads a copybook in free format if any of its lines can't be
+	// in fixed format, going by the text before its column 8, and in fixed
+	// format otherwise.
+	AutoFormat Format = iota
+
+	// FixedFormat is the reference format, in which only columns 8-72 are
+	// source text, and column 7 holds an indicator.
+	FixedFormat
+
+	// FreeFormat has no sequence, indicator or identification areas, so
+	// source text may start in any column and run to any length, and comments
+	// start with *>.
+	FreeFormat
+)
+
+// preprocessor reads a copybook in its source format, for the lexer.
+type preprocessor struct {
+	format   Format
+	comments bool // keep the text of comment lines, to document entries
+	debug    bool // read debugging lines as source, rather than comments
+
+	libraries []library // where the members named by COPY statements are found
+}
+
+// sourceLine is the text of a line, to which any continuation lines that
+// follow it are joined.
+type sourceLine struct {
+	text string
+	cols int // column at which the last line joined to it ends
+}
+
+// read returns src with all but its source text blanked out, so that the
+// lexer sees only the source text, at the same line and column as in src.
+// Compiler directives are blanked out, and a >>SOURCE directive switches the
+// format of the lines after it.
+//
+// In fixed format, the sequence and indicator areas are replaced by spaces,
+// and the identification area is dropped. Comment lines, and debugging lines
+// unless they're read as source, are blanked out, or passed on as *> comments
+// if their text is kept. Continuation lines are joined to the line of source
+// before them, either continuing the literal left open at its column 72, or
+// else the last word on it, and are then blanked out.
+//
+// In either format, *> comments are dropped unless their text is kept, as are
+// trailing spaces.
+func (p preprocessor) read(src string) string {
+	lines := strings.Split(src, "\n")
+	out := make([]sourceLine, len(lines))
+	format := p.format
+	if format == AutoFormat {
+		format = detectFormat(lines)
+	}
+
+	last := -1 // the last line of source text
+	for i, ln := range lines {
+		ln = strings.TrimRight(ln, "\r")
+		prefix, ind, area, cols := splitLine(ln)
+		f, ok := directive(ln)
+		if !ok && format == FixedFormat {
+			f, ok = directive(string(ind) + area)
+		}
+
+		if ok {
+			if f != AutoFormat {
+				format = f
+			}
+
+			continue
+		}
+
+		if format == FreeFormat {
+			out[i].text = p.uncomment(ln)
+			continue
+		}
+
+		switch {
+		case ind == commentIndicator, ind == pageEjectIndicator,
+			unicode.ToUpper(ind) == debuggingIndicator && !p.debug:
+			if p.comments {
+				out[i].text = prefix[:len(prefix)-len(commentPrefix)] + commentPrefix + area
+			}
+
+		case ind == continuationIndicator && last >= 0:
+			out[last].continueWith(p.uncomment(area), cols)
+
+		default:
+			out[i] = sourceLine{text: prefix + p.uncomment(area), cols: cols}
+			last = i
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(src))
+	for i, sl := range out {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(strings.TrimRight(sl.text, " \t"))
+	}
+
+	return b.String()
+}
+
+// splitLine splits the line ln into its sequence and indicator areas, as
+// spaces of the same width in bytes, so that positions in the line are kept,
+// the indicator, and Area A and B. It returns the number of columns up to the
+// end of Area B.
+func splitLine(ln string) (prefix string, ind rune, area string, cols int) {
+	ind = ' '
+	start, i := 0, 0
+	for cols < areaBEndColumn && i < len(ln) {
+		r, w := utf8.DecodeRuneInString(ln[i:])
+		i += w
+		cols++
+		if cols == indicatorColumn {
+			ind, start = r, i
+		}
+	}
+
+	if cols < indicatorColumn {
+		start = i
+	}
+
+	return strings.Repeat(" ", start), ind, ln[start:i], cols
+}
+
+// continueWith joins Area A and B of a continuation line, which ends at
+// column cols, to the line.
+func (sl *sourceLine) continueWith(area string, cols int) {
+	cont := strings.TrimLeft(area, " \t")
+	if q := openQuote(sl.text); q != 0 {
+		// the literal runs to column 72 of the line, and resumes after the
+		// quote that begins the continuation line
+		if sl.cols < areaBEndColumn {
+			sl.text += strings.Repeat(" ", areaBEndColumn-sl.cols)
+		}
+
+		cont = strings.TrimPrefix(cont, string(q))
+	} else {
+		sl.text = strings.TrimRight(sl.text, " \t")
+	}
+
+	sl.text += cont
+	sl.cols = cols
+}
+
+// uncomment returns s without any *> comment at its end, unless the text of
+// comments is kept.
+func (p preprocessor) uncomment(s string) string {
+	if p.comments {
+		return s
+	}
+
+	if _, i := scanLiterals(s); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// openQuote returns the quote of a literal left open at the end of s, or 0 if
+// there is none.
+func openQuote(s string) rune {
+	q, _ := scanLiterals(s)
+	return q
+}
+
+// scanLiterals scans s up to the *> comment that ends it, if there is one, and
+// returns the quote of any literal left open there, and the index of the
+// comment, or -1. Within a literal, a doubled quote stands for one.
+func scanLiterals(s string) (q rune, comment int) {
+	for i := 0; i < len(s); i++ {
+		switch c := rune(s[i]); {
+		case q == 0 && strings.HasPrefix(s[i:], commentPrefix):
+			return 0, i
+
+		case q == 0 && (c == singleQuote || c == doubleQuote):
+			q = c
+
+		case c == q && i+1 < len(s) && rune(s[i+1]) == q:
+			i++
+
+		case c == q:
+			q = 0
+		}
+	}
+
+	return q, -1
+}
+
+// directive reports whether the text s is a compiler directive, and returns
+// the format that it switches to, or AutoFormat if it doesn't. The format of
+// the lines after a directive of either of these forms is switched:
+//
+//	>>SOURCE [FORMAT] [IS] FREE
+//	>>SOURCE [FORMAT] [IS] FIXED
+func directive(s string) (Format, bool) {
+	words := strings.Fields(strings.ToUpper(s))
+	if len(words) == 0 || !strings.HasPrefix(words[0], directivePrefix) {
+		return AutoFormat, false
+	}
+
+	if words[0] != directivePrefix+"SOURCE" {
+		return AutoFormat, true
+	}
+
+	for _, w := range words[1:] {
+		switch w {
+		case "FORMAT", "IS":
+			continue
+		case "FREE":
+			return FreeFormat, true
+		case "FIXED":
+			return FixedFormat, true
+		}
+
+		break
+	}
+
+	return AutoFormat, true
+}
+
+// detectFormat returns the format of the lines of a copybook: free format, if
+// any of them before the first >>SOURCE directive can't be in fixed format,
+// going by the text before its Area A, and fixed format otherwise.
+func detectFormat(lines []string) Format {
+	for _, ln := range lines {
+		ln = strings.TrimRight(ln, "\r")
+		if strings.TrimFunc(ln, isLayout) == "" {
+			continue
+		}
+
+		prefix, ind, area, cols := splitLine(ln)
+		f, ok := directive(ln)
+		if !ok {
+			f, ok = directive(string(ind) + area)
+		}
+
+		if ok {
+			if f != AutoFormat {
+				return FixedFormat
+			}
+
+			continue
+		}
+
+		seq := ln[:len(prefix)]
+		switch {
+		case strings.Contains(seq, commentPrefix):
+			return FreeFormat
+
+		case cols < indicatorColumn:
+			// a short line may hold only a sequence number
+			if strings.TrimLeft(seq, "0123456789 \t") != "" {
+				return FreeFormat
+			}
+
+		case !isIndicator(ind):
+			return FreeFormat
+		}
+	}
+
+	return FixedFormat
+}
+
+// isLayout reports whether r is a space or a control character, such as the
+// SUBSTITUTE that may end a file, which says nothing of its format.
+func isLayout(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsControl(r)
+}
+
+// isIndicator reports whether r may appear in the indicator area.
+func isIndicator(r rune) bool {
+	switch unicode.ToUpper(r) {
+	case ' ', commentIndicator, pageEjectIndicator, debuggingIndicator, continuationIndicator:
+		return true
+	}
+
+	return false
+}
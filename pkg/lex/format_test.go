@@ -0,0 +1,120 @@
+package lex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_preprocessor_read(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		p    preprocessor
+		in   string
+		want string
+	}{
+		{
+			name: "SequenceAndIdentificationAreas",
+			in:   "000160     05  DUMMY-GROUP-1.                                           00000115\n",
+			want: "           05  DUMMY-GROUP-1.\n",
+		}, {
+			name: "AlphabeticSequenceArea",
+			in:   "ABC160     05  DUMMY-GROUP-1.                                           SOMEPROG\n",
+			want: "           05  DUMMY-GROUP-1.\n",
+		}, {
+			name: "BlankSequenceArea",
+			in:   "           05  DUMMY-GROUP-1.\n",
+			want: "           05  DUMMY-GROUP-1.\n",
+		}, {
+			name: "ShortLines",
+			in:   "0001\n\n000200\n",
+			want: "\n\n\n",
+		}, {
+			name: "CommentLines",
+			in:   "000010* A COMMENT\n000020/ A NEW PAGE\n000030D DEBUGGING\n000040     05  A PIC X.\n",
+			want: "\n\n\n           05  A PIC X.\n",
+		}, {
+			name: "CarriageReturns",
+			in:   "000160     05  DUMMY-GROUP-1.    \r\n000170     05  DUMMY-GROUP-2.\r\n",
+			want: "           05  DUMMY-GROUP-1.\n           05  DUMMY-GROUP-2.\n",
+		}, {
+			name: "NoTrailingNewline",
+			in:   "000160     05  DUMMY-GROUP-1.",
+			want: "           05  DUMMY-GROUP-1.",
+		}, {
+			name: "KeptComments",
+			p:    preprocessor{comments: true},
+			in:   "000010* A COMMENT\n000020/ A NEW PAGE\n000030D DEBUGGING\n",
+			want: "     *> A COMMENT\n     *> A NEW PAGE\n     *> DEBUGGING\n",
+		}, {
+			name: "DebuggingLines",
+			p:    preprocessor{debug: true},
+			in:   "000030D    05  A PIC X.\n000040d    05  B PIC X.\n",
+			want: "           05  A PIC X.\n           05  B PIC X.\n",
+		}, {
+			name: "ContinuedWord",
+			in:   "000010     05  ACCOUNT-NA\n000020-        ME PIC X.\n",
+			want: "           05  ACCOUNT-NAME PIC X.\n\n",
+		}, {
+			name: "ContinuedLiteral",
+			in: "000010     88  GREETING VALUE 'HELLO\n" +
+				"000020-    'WORLD'.\n",
+			want: "           88  GREETING VALUE 'HELLO" + strings.Repeat(" ", 72-36) + "WORLD'.\n\n",
+		}, {
+			name: "ContinuedLiteralAfterComment",
+			in: "000010     88  GREETING VALUE \"HE\"\"LLO" + strings.Repeat(" ", 72-38) + "00000010\n" +
+				"000020* A COMMENT\n" +
+				"000030-    \"WORLD\".\n",
+			want: "           88  GREETING VALUE \"HE\"\"LLO" + strings.Repeat(" ", 72-38) + "WORLD\".\n\n\n",
+		}, {
+			name: "InlineComments",
+			in:   "000010     05  A PIC X. *> A COMMENT\n000020     88  B VALUE '*>'. *> ANOTHER\n",
+			want: "           05  A PIC X.\n           88  B VALUE '*>'.\n",
+		}, {
+			name: "DetectedFreeFormat",
+			in:   "*> A COMMENT\n01 RECORD.\n  05 A PIC X(80).  *> A FIELD\n",
+			want: "\n01 RECORD.\n  05 A PIC X(80).\n",
+		}, {
+			name: "DetectedFreeFormatLongLine",
+			in:   "      01  RECORD.\n" + strings.Repeat(" ", 72) + "05 A PIC X.\n",
+			want: "      01  RECORD.\n" + strings.Repeat(" ", 72) + "05 A PIC X.\n",
+			p:    preprocessor{format: FreeFormat},
+		}, {
+			name: "DetectedFixedFormatWithSubstitute",
+			in:   "000010     05  A PIC X.\n\x1a",
+			want: "           05  A PIC X.\n",
+		}, {
+			name: "FreeFormatKeptComments",
+			p:    preprocessor{format: FreeFormat, comments: true},
+			in:   "*> A COMMENT\n05 A PIC X. *> A FIELD\n",
+			want: "*> A COMMENT\n05 A PIC X. *> A FIELD\n",
+		}, {
+			name: "FixedFormatSet",
+			p:    preprocessor{format: FixedFormat},
+			in:   "01 RECORD.\n000010     05  A PIC X.\n",
+			want: "       RD.\n           05  A PIC X.\n",
+		}, {
+			name: "SourceFormatDirectives",
+			in: "000010     01  RECORD.\n" +
+				"000020 >>SOURCE FORMAT IS FREE\n" +
+				"  05 A PIC X.\n" +
+				">>source fixed\n" +
+				"000050     05  B PIC X.\n",
+			want: "           01  RECORD.\n\n  05 A PIC X.\n\n           05  B PIC X.\n",
+		}, {
+			name: "OtherDirectives",
+			in:   ">>DEFINE DEBUGGING AS 1\n01 RECORD.\n",
+			want: "\n01 RECORD.\n",
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, tt.p.read(tt.in))
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package lex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level numbers with a meaning of their own.
+const (
+	recordLevel        = 1  // a record description
+	maxGroupLevel      = 49 // the last level that can be part of a group
+	renamesLevel       = 66 // a RENAMES of other items, which takes no storage
+	independentLevel   = 77 // an item that belongs to no record
+	conditionNameLevel = 88 // a condition name, for the values of the item before it
+)
+
+var (
+	// figurativeConstants are the words that stand for literals
+	figurativeConstants = map[string]struct{}{
+		"ZERO": {}, "ZEROS": {}, "ZEROES": {}, "SPACE": {}, "SPACES": {}, "HIGH-VALUE": {},
+		"HIGH-VALUES": {}, "LOW-VALUE": {}, "LOW-VALUES": {}, "QUOTE": {}, "QUOTES": {},
+		"NULL": {}, "NULLS": {},
+	}
+)
+
+// description is what the clauses of a data description entry say of the
+// item it describes.
+type description struct {
+	start     item     // the level number, where the entry starts
+	level     int      // the level number
+	name      item     // the data name, if any
+	filler    bool     // FILLER or unnamed item, which can't be referenced
+	redefines item     // data name of the item it redefines, if any
+	picture   item     // PIC clause, with the character-string as its value
+	occurs    int      // the most times the item occurs, if it's a table
+	separate  bool     // the sign takes a character of its own
+	usage     item     // USAGE clause, with the usage as its value
+	value     bool     // there's a VALUE clause
+	values    []string // values of a condition name
+	valueless bool     // the values include a range, so can't be listed
+	renames   bool     // there's a RENAMES clause
+	doc       string   // text of the comment lines before the entry, if kept
+}
+
+// entryParser parses the items of a data description entry by recursive
+// descent, following the grammar
+//
+//	entry        = level-number [data-name | FILLER] {clause}
+//	clause       = redefines | picture | occurs | sign | usage | value
+//	             | blank | justified | synchronized | EXTERNAL | GLOBAL
+//	             | renames
+//	redefines    = REDEFINES data-name
+//	picture      = (PIC | PICTURE) [IS] character-string
+//	occurs       = OCCURS integer [TO integer] [TIMES]
+//	               [DEPENDING [ON] data-name]
+//	               {(ASCENDING | DESCENDING) [KEY] [IS] data-name {data-name}}
+//	               [INDEXED [BY] index-name {index-name}]
+//	sign         = [SIGN [IS]] (LEADING | TRAILING) [SEPARATE [CHARACTER]]
+//	usage        = [USAGE [IS]] (BINARY | COMP | COMP-3 | DISPLAY | ...)
+//	value        = (VALUE [IS] | VALUES [ARE]) range {range}
+//	range        = literal [(THRU | THROUGH) literal]
+//	literal      = nonnumeric | numeric | [ALL] figurative-constant
+//	blank        = BLANK [WHEN] (ZERO | ZEROS | ZEROES)
+//	justified    = (JUST | JUSTIFIED) [RIGHT]
+//	synchronized = (SYNC | SYNCHRONIZED) [LEFT | RIGHT]
+//	renames      = RENAMES data-name [(THRU | THROUGH) data-name]
+//	data-name    = name {(OF | IN) name}
+//
+// The clauses may come in any order, but none of them twice. Reserved words
+// are the same whatever their case.
+type entryParser struct {
+	t     *Tree
+	items []item
+	i     int // index of the next item
+	d     *description
+	seen  map[string]bool // clauses already parsed
+}
+
+// parseEntry parses the entry e into a description of its item. If it can't
+// make sense of any of it, it returns an *Error saying where and why.
+func (t *Tree) parseEntry(e entry) (*description, error) {
+	p := &entryParser{t: t, items: e.items, d: &description{doc: e.doc}, seen: map[string]bool{}}
+	if err := p.parseLevel(); err != nil {
+		return nil, err
+	}
+
+	p.parseName()
+	for !p.done() {
+		if err := p.parseClause(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.check(); err != nil {
+		return nil, err
+	}
+
+	return p.d, nil
+}
+
+// done reports whether every item of the entry has been parsed.
+func (p *entryParser) done() bool {
+	return p.i >= len(p.items)
+}
+
+// peek returns, but does not move past, the next item of the entry, which is
+// the zero item at its end.
+func (p *entryParser) peek() item {
+	if p.done() {
+		return item{}
+	}
+
+	return p.items[p.i]
+}
+
+// next returns the next item of the entry, and moves past it.
+func (p *entryParser) next() item {
+	it := p.peek()
+	if !p.done() {
+		p.i++
+	}
+
+	return it
+}
+
+// keyword moves past the next item if it's one of the words, and reports
+// whether it was.
+func (p *entryParser) keyword(words ...string) bool {
+	it := p.peek()
+	if it.typ != itemKeyword {
+		return false
+	}
+
+	for _, w := range words {
+		if strings.EqualFold(it.val, w) {
+			p.i++
+			return true
+		}
+	}
+
+	return false
+}
+
+// atName reports whether the next item is a name, rather than a keyword.
+func (p *entryParser) atName() bool {
+	return p.peek().typ == itemIdentifier
+}
+
+// once returns an Error for the item it, if it begins a clause that has
+// already been parsed.
+func (p *entryParser) once(it item, clause string) error {
+	if p.seen[clause] {
+		return p.errorf(it, "%s clause is repeated", clause)
+	}
+
+	p.seen[clause] = true
+	return nil
+}
+
+// errorf returns an Error for the item it.
+func (p *entryParser) errorf(it item, format string, args ...interface{}) error {
+	return p.t.errorFor(it, fmt.Errorf(format, args...))
+}
+
+// expected returns an Error for the next item, which isn't what was expected,
+// or for the last, if the entry ends too soon.
+func (p *entryParser) expected(what string) error {
+	if p.done() {
+		last := p.items[len(p.items)-1]
+		return p.errorf(last, "expected %s after %s", what, last)
+	}
+
+	return p.errorf(p.peek(), "expected %s, found %s", what, p.peek())
+}
+
+func (p *entryParser) parseLevel() error {
+	it := p.next()
+	n, err := strconv.Atoi(it.val)
+	if it.typ != itemNumber || err != nil || !isLevel(n) {
+		return p.errorf(it, "expected a level number, found %s", it)
+	}
+
+	p.d.start, p.d.level = it, n
+	return nil
+}
+
+// parseName parses the data name of the entry, if it has one. Items without
+// one are FILLER.
+func (p *entryParser) parseName() {
+	if p.atName() || p.peek().typ == itemKeyword && isFiller(p.peek().val) {
+		p.d.name = p.next()
+	}
+
+	p.d.filler = isFiller(p.d.name.val)
+}
+
+func (p *entryParser) parseClause() error { // nolint:gocyclo // one case per clause
+	it := p.peek()
+	switch it.typ {
+	case itemPIC:
+		return p.parsePicture()
+
+	case itemKeyword:
+		switch w := strings.ToUpper(it.val); {
+		case w == "OCCURS":
+			return p.parseOccurs()
+
+		case w == "REDEFINES":
+			return p.parseRedefines()
+
+		case w == "VALUE", w == "VALUES":
+			return p.parseValue()
+
+		case w == "USAGE", isUsage(w):
+			return p.parseUsage()
+
+		case w == "SIGN", w == "LEADING", w == "TRAILING":
+			return p.parseSign()
+
+		case w == "BLANK":
+			if err := p.once(p.next(), w); err != nil {
+				return err
+			}
+
+			p.keyword("WHEN")
+			if !p.keyword("ZERO", "ZEROS", "ZEROES") {
+				return p.expected("ZERO")
+			}
+
+			return nil
+
+		case w == "JUST", w == "JUSTIFIED":
+			if err := p.once(p.next(), "JUSTIFIED"); err != nil {
+				return err
+			}
+
+			p.keyword("RIGHT")
+			return nil
+
+		case w == "SYNC", w == "SYNCHRONIZED":
+			if err := p.once(p.next(), "SYNCHRONIZED"); err != nil {
+				return err
+			}
+
+			p.keyword("LEFT", "RIGHT")
+			return nil
+
+		case w == "EXTERNAL", w == "GLOBAL":
+			return p.once(p.next(), w)
+
+		case w == "RENAMES":
+			return p.parseRenames()
+		}
+	}
+
+	return p.errorf(it, "unexpected %s", it)
+}
+
+func (p *entryParser) parsePicture() error {
+	it := p.next()
+	if err := p.once(it, "PIC"); err != nil {
+		return err
+	}
+
+	p.keyword("IS")
+	if p.peek().typ != itemPicture {
+		return p.expected("a picture character-string")
+	}
+
+	// picture symbols are the same whatever their case
+	p.d.picture = p.next()
+	p.d.picture.val = strings.ToUpper(p.d.picture.val)
+	return nil
+}
+
+func (p *entryParser) parseOccurs() error {
+	it := p.next()
+	if err := p.once(it, "OCCURS"); err != nil {
+		return err
+	}
+
+	n, err := p.parseInteger()
+	if err != nil {
+		return err
+	}
+
+	// a table that occurs a variable number of times takes up the most
+	if p.keyword("TO") {
+		if n, err = p.parseInteger(); err != nil {
+			return err
+		}
+	}
+
+	if n < 1 {
+		return p.errorf(it, "OCCURS count must be at least 1")
+	}
+
+	p.keyword("TIMES")
+	if p.keyword("DEPENDING") {
+		p.keyword("ON")
+		if err := p.parseDataName(); err != nil {
+			return err
+		}
+	}
+
+	for p.keyword("ASCENDING", "DESCENDING") {
+		p.keyword("KEY")
+		p.keyword("IS")
+		if err := p.parseDataNames(); err != nil {
+			return err
+		}
+	}
+
+	if p.keyword("INDEXED") {
+		p.keyword("BY")
+		if err := p.parseDataNames(); err != nil {
+			return err
+		}
+	}
+
+	p.d.occurs = n
+	return nil
+}
+
+func (p *entryParser) parseInteger() (int, error) {
+	it := p.peek()
+	n, err := strconv.Atoi(it.val)
+	if it.typ != itemNumber || err != nil || n < 0 {
+		return 0, p.expected("an integer")
+	}
+
+	p.next()
+	return n, nil
+}
+
+func (p *entryParser) parseRedefines() error {
+	if err := p.once(p.next(), "REDEFINES"); err != nil {
+		return err
+	}
+
+	if !p.atName() {
+		return p.expected("the data name of the item it redefines")
+	}
+
+	p.d.redefines = p.next()
+	return nil
+}
+
+func (p *entryParser) parseSign() error {
+	if err := p.once(p.peek(), "SIGN"); err != nil {
+		return err
+	}
+
+	if p.keyword("SIGN") {
+		p.keyword("IS")
+	}
+
+	if !p.keyword("LEADING", "TRAILING") {
+		return p.expected("LEADING or TRAILING")
+	}
+
+	if p.keyword("SEPARATE") {
+		p.d.separate = true
+		p.keyword("CHARACTER")
+	}
+
+	return nil
+}
+
+func (p *entryParser) parseUsage() error {
+	if err := p.once(p.peek(), "USAGE"); err != nil {
+		return err
+	}
+
+	if p.keyword("USAGE") {
+		p.keyword("IS")
+	}
+
+	if !isUsage(p.peek().val) || p.peek().typ != itemKeyword {
+		return p.expected("a usage")
+	}
+
+	p.d.usage = p.next()
+	return nil
+}
+
+func (p *entryParser) parseValue() error {
+	if err := p.once(p.next(), "VALUE"); err != nil {
+		return err
+	}
+
+	p.d.value = true
+	p.keyword("IS", "ARE")
+	if !p.atLiteral() {
+		return p.expected("a literal")
+	}
+
+	for p.atLiteral() {
+		v, ok := p.parseLiteral()
+		if !ok {
+			return p.expected("a literal")
+		}
+
+		p.d.values = append(p.d.values, v)
+		if p.keyword("THRU", "THROUGH") {
+			if _, ok := p.parseLiteral(); !ok {
+				return p.expected("a literal")
+			}
+
+			// ranges can't be represented as a list of values
+			p.d.valueless = true
+		}
+	}
+
+	return nil
+}
+
+// atLiteral reports whether the next item begins a literal.
+func (p *entryParser) atLiteral() bool {
+	switch it := p.peek(); it.typ {
+	case itemEnum, itemNumber:
+		return true
+
+	case itemKeyword:
+		_, ok := figurativeConstants[strings.ToUpper(it.val)]
+		return ok || strings.EqualFold(it.val, "ALL")
+	}
+
+	return false
+}
+
+// parseLiteral parses a literal, and returns its value. Figurative constants
+// stand for as many characters as the item takes, so have no value of their
+// own, and make the values of the entry valueless.
+func (p *entryParser) parseLiteral() (string, bool) {
+	all := p.keyword("ALL")
+	switch it := p.peek(); {
+	case it.typ == itemEnum:
+		p.next()
+		p.d.valueless = p.d.valueless || all
+		return unquote(it.val), true
+
+	case it.typ == itemNumber && !all:
+		p.next()
+		return it.val, true
+
+	case p.atLiteral():
+		p.next()
+		p.d.valueless = true
+		return strings.ToUpper(it.val), true
+	}
+
+	return "", false
+}
+
+func (p *entryParser) parseRenames() error {
+	if err := p.once(p.next(), "RENAMES"); err != nil {
+		return err
+	}
+
+	p.d.renames = true
+	if err := p.parseDataName(); err != nil {
+		return err
+	}
+
+	if p.keyword("THRU", "THROUGH") {
+		return p.parseDataName()
+	}
+
+	return nil
+}
+
+// parseDataName parses a data name, which may be qualified by the names of
+// the groups it's in.
+func (p *entryParser) parseDataName() error {
+	if !p.atName() {
+		return p.expected("a data name")
+	}
+
+	p.next()
+	for p.keyword("OF", "IN") {
+		if !p.atName() {
+			return p.expected("a data name")
+		}
+
+		p.next()
+	}
+
+	return nil
+}
+
+// parseDataNames parses one or more data names.
+func (p *entryParser) parseDataNames() error {
+	if err := p.parseDataName(); err != nil {
+		return err
+	}
+
+	for p.atName() {
+		if err := p.parseDataName(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check reports an Error for an entry whose clauses don't belong together.
+func (p *entryParser) check() error {
+	d := p.d
+	switch {
+	case d.level == conditionNameLevel && !d.value:
+		return p.errorf(d.start, "condition name has no VALUE clause")
+
+	case d.level == renamesLevel && !d.renames:
+		return p.errorf(d.start, "level 66 entry has no RENAMES clause")
+
+	case d.level != renamesLevel && d.renames:
+		return p.errorf(d.start, "RENAMES clause must be at level 66")
+	}
+
+	return nil
+}
+
+// isLevel reports whether n is a level number.
+func isLevel(n int) bool {
+	switch n {
+	case renamesLevel, independentLevel, conditionNameLevel:
+		return true
+	}
+
+	return n >= recordLevel && n <= maxGroupLevel
+}
+
+// isUsage reports whether w is a usage, whatever its case.
+func isUsage(w string) bool {
+	_, ok := usages[strings.ToUpper(w)]
+	return ok
+}
@@ -0,0 +1,235 @@
+package lex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseEntry(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		in        string
+		level     int
+		dataName  string
+		filler    bool
+		redefines string
+		picture   string
+		occurs    int
+		separate  bool
+		values    []string
+		valueless bool
+	}{
+		{
+			name:     "Group",
+			in:       "       05  DUMMY-GROUP-1.",
+			level:    5,
+			dataName: "DUMMY-GROUP-1",
+		}, {
+			name:     "ClausesInAnyOrder",
+			in:       "       05  DUMMY-TABLE OCCURS 3 TIMES PIC X(2) VALUE 'AB'.",
+			level:    5,
+			dataName: "DUMMY-TABLE",
+			picture:  "X(2)",
+			occurs:   3,
+			values:   []string{"AB"},
+		}, {
+			name:      "PICIs",
+			in:        "       05  DUMMY-OBJECT PIC IS 9(4) VALUE IS ZERO.",
+			level:     5,
+			dataName:  "DUMMY-OBJECT",
+			picture:   "9(4)",
+			values:    []string{"ZERO"},
+			valueless: true,
+		}, {
+			name:     "Usage",
+			in:       "       05  DUMMY-OBJECT PIC S9(4) USAGE IS COMP SYNC.",
+			level:    5,
+			dataName: "DUMMY-OBJECT",
+			picture:  "S9(4)",
+		}, {
+			name:     "UsageWithoutKeyword",
+			in:       "       05  DUMMY-OBJECT COMP-3 PIC S9(7)V99.",
+			level:    5,
+			dataName: "DUMMY-OBJECT",
+			picture:  "S9(7)V99",
+		}, {
+			name:     "VariableOccurs",
+			in:       "       05  DUMMY-TABLE PIC X OCCURS 1 TO 10 TIMES DEPENDING DUMMY-COUNT\n           ASCENDING KEY IS DUMMY-KEY INDEXED BY DUMMY-INDEX.",
+			level:    5,
+			dataName: "DUMMY-TABLE",
+			picture:  "X",
+			occurs:   10,
+		}, {
+			name:      "Redefines",
+			in:        "       05  DUMMY-OBJECT-B REDEFINES DUMMY-OBJECT-A PIC X.",
+			level:     5,
+			dataName:  "DUMMY-OBJECT-B",
+			redefines: "DUMMY-OBJECT-A",
+			picture:   "X",
+		}, {
+			name:     "SignSeparate",
+			in:       "       05  DUMMY-OBJECT PIC S9(3) TRAILING SEPARATE CHARACTER.",
+			level:    5,
+			dataName: "DUMMY-OBJECT",
+			picture:  "S9(3)",
+			separate: true,
+		}, {
+			name:    "Anonymous",
+			in:      "       05  PIC X(3) BLANK WHEN ZERO.",
+			level:   5,
+			filler:  true,
+			picture: "X(3)",
+		}, {
+			name:     "Filler",
+			in:       "       05  FILLER PIC X(3) JUST.",
+			level:    5,
+			dataName: "FILLER",
+			filler:   true,
+			picture:  "X(3)",
+		}, {
+			name:     "ConditionNameValues",
+			in:       "       88  DUMMY-VALID VALUES ARE 'A', 'B'\n           'C'.",
+			level:    88,
+			dataName: "DUMMY-VALID",
+			values:   []string{"A", "B", "C"},
+		}, {
+			name:      "ConditionNameRange",
+			in:        "       88  DUMMY-LOW VALUE 1 THROUGH 4.",
+			level:     88,
+			dataName:  "DUMMY-LOW",
+			values:    []string{"1"},
+			valueless: true,
+		}, {
+			name:      "ConditionNameFigurativeConstant",
+			in:        "       88  DUMMY-BLANK VALUE SPACES.",
+			level:     88,
+			dataName:  "DUMMY-BLANK",
+			values:    []string{"SPACES"},
+			valueless: true,
+		}, {
+			name:      "LowerCase",
+			in:        "       05  dummy-table picture is s9(3) sign trailing separate\n           occurs 2 times value zero.",
+			level:     5,
+			dataName:  "dummy-table",
+			picture:   "S9(3)",
+			occurs:    2,
+			separate:  true,
+			values:    []string{"ZERO"},
+			valueless: true,
+		}, {
+			name:     "LowerCaseFiller",
+			in:       "       05  filler pic x.",
+			level:    5,
+			dataName: "filler",
+			filler:   true,
+			picture:  "X",
+		}, {
+			name:     "IndependentItem",
+			in:       "       77  DUMMY-OBJECT PIC 9 EXTERNAL.",
+			level:    77,
+			dataName: "DUMMY-OBJECT",
+			picture:  "9",
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tree := NewTree(New("test", tt.in))
+			entries, err := tree.scanEntries()
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+
+			d, err := tree.parseEntry(entries[0])
+			require.NoError(t, err)
+			require.Equal(t, tt.level, d.level)
+			require.Equal(t, tt.dataName, d.name.val)
+			require.Equal(t, tt.filler, d.filler)
+			require.Equal(t, tt.redefines, d.redefines.val)
+			require.Equal(t, tt.picture, d.picture.val)
+			require.Equal(t, tt.occurs, d.occurs)
+			require.Equal(t, tt.separate, d.separate)
+			require.Equal(t, tt.values, d.values)
+			require.Equal(t, tt.valueless, d.valueless)
+		})
+	}
+}
+
+func Test_parseEntry_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		in     string
+		column int
+		want   string
+	}{
+		{
+			name:   "UnknownClause",
+			in:     "       05  DUMMY-OBJECT PIC X FROB.",
+			column: 31,
+			want:   `unexpected "FROB"`,
+		}, {
+			name:   "RepeatedClause",
+			in:     "       05  DUMMY-OBJECT PIC X PIC 9.",
+			column: 31,
+			want:   "PIC clause is repeated",
+		}, {
+			name:   "BadLevelNumber",
+			in:     "       99  DUMMY-OBJECT PIC X.",
+			column: 8,
+			want:   `expected a level number, found "99"`,
+		}, {
+			name:   "MissingLevelNumber",
+			in:     "       DUMMY-OBJECT PIC X.",
+			column: 8,
+			want:   `expected a level number, found "DUMMY-OBJECT"`,
+		}, {
+			name:   "BadOccursCount",
+			in:     "       05  DUMMY-TABLE PIC X OCCURS SOME TIMES.",
+			column: 37,
+			want:   `expected an integer, found "SOME"`,
+		}, {
+			name:   "MissingLiteral",
+			in:     "       05  DUMMY-OBJECT PIC X VALUE.",
+			column: 31,
+			want:   `expected a literal after "VALUE"`,
+		}, {
+			name:   "ReservedWordAsDataName",
+			in:     "       05  DATE PIC X.",
+			column: 12,
+			want:   `unexpected "DATE"`,
+		}, {
+			name:   "MissingPictureString",
+			in:     "       05  DUMMY-OBJECT PICTURE IS.",
+			column: 33,
+			want:   `expected a picture character-string after "IS"`,
+		}, {
+			name:   "ConditionNameWithoutValue",
+			in:     "       88  DUMMY-CONDITION.",
+			column: 8,
+			want:   "condition name has no VALUE clause",
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tree := NewTree(New("test", tt.in))
+			entries, err := tree.scanEntries()
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+
+			_, err = tree.parseEntry(entries[0])
+			var e *Error
+			require.True(t, errors.As(err, &e), "want an *Error, got %v", err)
+			require.Equal(t, 1, e.Line)
+			require.Equal(t, tt.column, e.Column)
+			require.EqualError(t, e.Err, tt.want)
+		})
+	}
+}
@@ -29,35 +29,23 @@ type itemType int
 
 const (
 	itemError      itemType = iota // error occurred; value is text of error
-	itemBool                       // boolean constant
 	itemChar                       // printable ASCII character; grab bag for comma etc.
 	itemComplex                    // complex constant (1+2i); imaginary is just a number
 	itemEOF                        // end of file
 	itemEOL                        // end of line
 	itemIdentifier                 // Name of PIC or group
+	itemKeyword                    // reserved word, e.g. OCCURS, REDEFINES or VALUE, in any case
 	itemNumber                     // simple number, including imaginary
 	itemSpace                      // run of spaces separating arguments
 	itemDot                        // the cursor, spelled '.'
-	itemOCCURS                     // OCCURS keyword
-	itemPIC                        // PIC keyword
-	itemREDEFINES                  // REDEFINES keyword
+	itemPIC                        // PIC or PICTURE keyword
+	itemPicture                    // character-string of a PIC clause, e.g. S9(7)V99
 	itemEnum                       // enum example: 'Y' 'N' 'T' 'F'
+	itemComment                    // comment, from *> to the end of the line
 )
 
 const (
 	eof        = -1
-	picLeft    = 'P'
-	picRight   = '.'
 	leftParen  = '('
 	rightParen = ')'
 )
-
-var (
-	picChars = map[rune]struct{}{
-		'P': {}, 'I': {}, 'C': {}, leftParen: {}, rightParen: {}, 'X': {}, '9': {}, 'S': {}, 'V': {},
-	}
-
-	picTypes = map[rune]struct{}{
-		'X': {}, '9': {}, 'S': {}, 'V': {},
-	}
-)
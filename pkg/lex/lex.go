@@ -2,7 +2,6 @@ package lex
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"unicode/utf8"
 )
@@ -12,31 +11,46 @@ type stateFn func(*lexer) stateFn
 
 // lexer holds the state of the scanner.
 type lexer struct {
-	name      string // the name of the input; used only for error reports
-	input     string // the string being scanned
-	pos       Pos    // current position in the input
-	start     Pos    // start position of this item
-	width     Pos    // width of last rune read from input
-	items     []item // channel of scanned items
-	line      int    // 1+number of newlines seen
-	startLine int    // start line of this item
+	name      string       // the name of the input; used only for error reports
+	input     string       // the string being scanned
+	spans     []span       // the members that the input comes from
+	copyErr   error        // why a COPY statement couldn't be expanded, if one couldn't
+	pos       Pos          // current position in the input
+	start     Pos          // start position of this item
+	width     Pos          // width of last rune read from input
+	items     []item       // channel of scanned items
+	line      int          // 1+number of newlines seen
+	startLine int          // start line of this item
+	log       Logger       // where progress is reported, if anywhere
+	pre       preprocessor // how the source is read
 }
 
 type Lexer interface {
 	getNext() item
 	getName() string
+	logger() Logger
+	locate(p Pos) position
 }
 
-// New creates a new scanner for the input string.
-func New(name, input string) Lexer {
-	log.Println("building new lexer")
+// New creates a new scanner for the input string, a copybook in fixed
+// reference format or free format, which is detected unless it's set with
+// WithFormat. The COPY statements in it are expanded with the members they
+// name, from the libraries set with WithCopybooks.
+func New(name, input string, opts ...Option) Lexer {
 	l := &lexer{
 		name:      name,
-		input:     input,
 		items:     make([]item, 0),
 		line:      1,
 		startLine: 1,
 	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.expand(&member{name: name, source: input, text: l.pre.read(input)})
+
+	l.logf("building new lexer")
 	l.run()
 	return l
 }
@@ -45,6 +59,10 @@ func (l *lexer) getName() string {
 	return l.name
 }
 
+func (l *lexer) logger() Logger {
+	return l.log
+}
+
 // run runs the state machine for the lexer.
 func (l *lexer) run() {
 	for state := lexInsideStatement(l); state != nil; {
@@ -69,10 +87,13 @@ func (l *lexer) next() rune {
 	return r
 }
 
-// peek returns but does not consume the next rune in the input.
+// peek returns but does not consume the next rune in the input, so that the
+// last rune read may still be backed up over.
 func (l *lexer) peek() rune {
+	w := l.width
 	r := l.next()
 	l.backup()
+	l.width = w
 	return r
 }
 
@@ -147,7 +168,7 @@ func (l *lexer) errorf(format string, args ...interface{}) stateFn {
 func (l *lexer) getNext() item {
 	var next item
 	if len(l.items) == 0 {
-		log.Println("no lexed items left in stack")
+		l.logf("no lexed items left in stack")
 		return item{}
 	}
 
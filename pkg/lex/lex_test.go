@@ -1,6 +1,9 @@
 package lex
 
 import (
+	"bytes"
+	"log"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,7 +29,9 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 17, val: "  ", line: 0},
 				{typ: itemIdentifier, pos: 19, val: "X710203-STATEMENT-TYPE", line: 0},
 				{typ: itemSpace, pos: 41, val: "       ", line: 0},
-				{typ: itemPIC, pos: 48, val: "PIC X", line: 0},
+				{typ: itemPIC, pos: 48, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 51, val: " ", line: 0},
+				{typ: itemPicture, pos: 52, val: "X", line: 0},
 				{typ: itemDot, pos: 53, val: ".", line: 0},
 				{typ: itemSpace, pos: 54, val: "                  ", line: 0},
 				{typ: itemNumber, pos: 72, val: "00000167", line: 0},
@@ -47,7 +52,9 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 17, val: "  ", line: 0},
 				{typ: itemIdentifier, pos: 19, val: "P-OBJECT", line: 0},
 				{typ: itemSpace, pos: 27, val: "       ", line: 0},
-				{typ: itemPIC, pos: 34, val: "PIC X", line: 0},
+				{typ: itemPIC, pos: 34, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 37, val: " ", line: 0},
+				{typ: itemPicture, pos: 38, val: "X", line: 0},
 				{typ: itemDot, pos: 39, val: ".", line: 0},
 				{typ: itemSpace, pos: 40, val: "                  ", line: 0},
 				{typ: itemNumber, pos: 58, val: "00000167", line: 0},
@@ -68,7 +75,9 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 17, val: "  ", line: 0},
 				{typ: itemIdentifier, pos: 19, val: "X710203-STATEMENT-TYPE", line: 0},
 				{typ: itemSpace, pos: 41, val: "       ", line: 0},
-				{typ: itemPIC, pos: 48, val: "PIC X(10)", line: 0},
+				{typ: itemPIC, pos: 48, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 51, val: " ", line: 0},
+				{typ: itemPicture, pos: 52, val: "X(10)", line: 0},
 				{typ: itemDot, pos: 57, val: ".", line: 0},
 				{typ: itemSpace, pos: 58, val: "                  ", line: 0},
 				{typ: itemNumber, pos: 76, val: "00000167", line: 0},
@@ -90,7 +99,7 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 21, val: "  "},
 				{typ: itemIdentifier, pos: 23, val: "X710203-SORT-CNTRY-CD"},
 				{typ: itemSpace, pos: 44, val: "    "},
-				{typ: itemREDEFINES, pos: 48, val: "REDEFINES"},
+				{typ: itemKeyword, pos: 48, val: "REDEFINES"},
 				{typ: itemSpace, pos: 57, val: "               "},
 				{typ: itemNumber, pos: 72, val: "00000142"},
 				{typ: itemEOL, pos: 80, val: "\n"},
@@ -98,7 +107,9 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 87, val: "                 ", line: 1},
 				{typ: itemIdentifier, pos: 104, val: "X710203-DOCUMENT-ID-TIE", line: 1},
 				{typ: itemSpace, pos: 127, val: "  ", line: 1},
-				{typ: itemPIC, pos: 129, val: "PIC XX", line: 1},
+				{typ: itemPIC, pos: 129, val: "PIC", line: 1},
+				{typ: itemSpace, pos: 132, val: " ", line: 1},
+				{typ: itemPicture, pos: 133, val: "XX", line: 1},
 				{typ: itemDot, pos: 135, val: ".", line: 1},
 				{typ: itemSpace, pos: 136, val: "                 ", line: 1},
 				{typ: itemNumber, pos: 153, val: "00000143", line: 1},
@@ -120,15 +131,50 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 17, val: "  ", line: 0},
 				{typ: itemIdentifier, pos: 19, val: "X710203-STATEMENT-TYPE", line: 0},
 				{typ: itemSpace, pos: 41, val: "       ", line: 0},
-				{typ: itemPIC, pos: 48, val: "PIC X(10)", line: 0},
+				{typ: itemPIC, pos: 48, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 51, val: " ", line: 0},
+				{typ: itemPicture, pos: 52, val: "X(10)", line: 0},
 				{typ: itemSpace, pos: 57, val: "  ", line: 0},
-				{typ: itemOCCURS, pos: 59, val: "OCCURS 2", line: 0},
+				{typ: itemKeyword, pos: 59, val: "OCCURS", line: 0},
+				{typ: itemSpace, pos: 65, val: " ", line: 0},
+				{typ: itemNumber, pos: 66, val: "2", line: 0},
 				{typ: itemDot, pos: 67, val: ".", line: 0},
 				{typ: itemSpace, pos: 68, val: "  ", line: 0},
 				{typ: itemNumber, pos: 70, val: "00000167", line: 0},
 				{typ: itemEOL, pos: 78, val: "\n", line: 0},
 				{typ: itemEOF, pos: 79, val: "", line: 1},
 			},
+		}, { // nolint:dupl
+			name: "SimplePICWithParentheses_SIGN",
+			l: &lexer{
+				name:  "lexer",
+				input: "000600         10  X710203-AMOUNT   PIC S9(7)V99 SIGN IS LEADING SEPARATE.  00000167\n",
+				items: make([]item, 0),
+			},
+			want: []item{
+				{typ: itemNumber, pos: 0, val: "000600", line: 0},
+				{typ: itemSpace, pos: 6, val: "         ", line: 0},
+				{typ: itemNumber, pos: 15, val: "10", line: 0},
+				{typ: itemSpace, pos: 17, val: "  ", line: 0},
+				{typ: itemIdentifier, pos: 19, val: "X710203-AMOUNT", line: 0},
+				{typ: itemSpace, pos: 33, val: "   ", line: 0},
+				{typ: itemPIC, pos: 36, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 39, val: " ", line: 0},
+				{typ: itemPicture, pos: 40, val: "S9(7)V99", line: 0},
+				{typ: itemSpace, pos: 48, val: " ", line: 0},
+				{typ: itemKeyword, pos: 49, val: "SIGN", line: 0},
+				{typ: itemSpace, pos: 53, val: " ", line: 0},
+				{typ: itemKeyword, pos: 54, val: "IS", line: 0},
+				{typ: itemSpace, pos: 56, val: " ", line: 0},
+				{typ: itemKeyword, pos: 57, val: "LEADING", line: 0},
+				{typ: itemSpace, pos: 64, val: " ", line: 0},
+				{typ: itemKeyword, pos: 65, val: "SEPARATE", line: 0},
+				{typ: itemDot, pos: 73, val: ".", line: 0},
+				{typ: itemSpace, pos: 74, val: "  ", line: 0},
+				{typ: itemNumber, pos: 76, val: "00000167", line: 0},
+				{typ: itemEOL, pos: 84, val: "\n", line: 0},
+				{typ: itemEOF, pos: 85, val: "", line: 1},
+			},
 		}, { // nolint:dupl // test data
 			name: "SimplePICWithParentheses_FloatExplicitDecimalPoint",
 			l: &lexer{
@@ -143,7 +189,9 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 17, val: "  ", line: 0},
 				{typ: itemIdentifier, pos: 19, val: "X710203-STATEMENT-TYPE", line: 0},
 				{typ: itemSpace, pos: 41, val: "       ", line: 0},
-				{typ: itemPIC, pos: 48, val: "PIC 9(10).9(3)", line: 0},
+				{typ: itemPIC, pos: 48, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 51, val: " ", line: 0},
+				{typ: itemPicture, pos: 52, val: "9(10).9(3)", line: 0},
 				{typ: itemDot, pos: 62, val: ".", line: 0},
 				{typ: itemSpace, pos: 63, val: "                  ", line: 0},
 				{typ: itemNumber, pos: 81, val: "00000167", line: 0},
@@ -163,7 +211,7 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 5, val: "   ", line: 0},
 				{typ: itemIdentifier, pos: 8, val: "EXAMPLE-ENUM", line: 0},
 				{typ: itemSpace, pos: 20, val: "  ", line: 0},
-				{typ: itemIdentifier, pos: 22, val: "VALUE", line: 0},
+				{typ: itemKeyword, pos: 22, val: "VALUE", line: 0},
 				{typ: itemSpace, pos: 27, val: "  ", line: 0},
 				{typ: itemEnum, pos: 29, val: "'N'", line: 0},
 				{typ: itemDot, pos: 32, val: ".", line: 0},
@@ -185,7 +233,7 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemSpace, pos: 11, val: "   ", line: 0},
 				{typ: itemIdentifier, pos: 14, val: "EXAMPLE-ENUM", line: 0},
 				{typ: itemSpace, pos: 26, val: "  ", line: 0},
-				{typ: itemIdentifier, pos: 28, val: "VALUE", line: 0},
+				{typ: itemKeyword, pos: 28, val: "VALUE", line: 0},
 				{typ: itemSpace, pos: 33, val: "  ", line: 0},
 				{typ: itemEnum, pos: 35, val: "'N'", line: 0},
 				{typ: itemDot, pos: 38, val: ".", line: 0},
@@ -194,6 +242,83 @@ func Test_lexer_run(t *testing.T) {
 				{typ: itemEOL, pos: 48, val: "\n", line: 0},
 				{typ: itemEOF, pos: 49, val: "", line: 1},
 			},
+		}, {
+			name: "LiteralsAndComments",
+			l: &lexer{
+				name:  "lexer",
+				input: "   88  QUOTED  VALUE \"A \"\"B\"\"\"  'C''S'. *> NOTE\n",
+				items: make([]item, 0),
+			},
+			want: []item{
+				{typ: itemSpace, pos: 0, val: "   ", line: 0},
+				{typ: itemNumber, pos: 3, val: "88", line: 0},
+				{typ: itemSpace, pos: 5, val: "  ", line: 0},
+				{typ: itemIdentifier, pos: 7, val: "QUOTED", line: 0},
+				{typ: itemSpace, pos: 13, val: "  ", line: 0},
+				{typ: itemKeyword, pos: 15, val: "VALUE", line: 0},
+				{typ: itemSpace, pos: 20, val: " ", line: 0},
+				{typ: itemEnum, pos: 21, val: "\"A \"\"B\"\"\"", line: 0},
+				{typ: itemSpace, pos: 30, val: "  ", line: 0},
+				{typ: itemEnum, pos: 32, val: "'C''S'", line: 0},
+				{typ: itemDot, pos: 38, val: ".", line: 0},
+				{typ: itemSpace, pos: 39, val: " ", line: 0},
+				{typ: itemComment, pos: 40, val: "*> NOTE", line: 0},
+				{typ: itemEOL, pos: 47, val: "\n", line: 0},
+				{typ: itemEOF, pos: 48, val: "", line: 1},
+			},
+		}, {
+			name: "WordsStartingWithKeywordLetters",
+			l: &lexer{
+				name:  "lexer",
+				input: "   05  ORDER-ID REDEFINES RATE-CODE PIC S9.\n",
+				items: make([]item, 0),
+			},
+			want: []item{
+				{typ: itemSpace, pos: 0, val: "   ", line: 0},
+				{typ: itemNumber, pos: 3, val: "05", line: 0},
+				{typ: itemSpace, pos: 5, val: "  ", line: 0},
+				{typ: itemIdentifier, pos: 7, val: "ORDER-ID", line: 0},
+				{typ: itemSpace, pos: 15, val: " ", line: 0},
+				{typ: itemKeyword, pos: 16, val: "REDEFINES", line: 0},
+				{typ: itemSpace, pos: 25, val: " ", line: 0},
+				{typ: itemIdentifier, pos: 26, val: "RATE-CODE", line: 0},
+				{typ: itemSpace, pos: 35, val: " ", line: 0},
+				{typ: itemPIC, pos: 36, val: "PIC", line: 0},
+				{typ: itemSpace, pos: 39, val: " ", line: 0},
+				{typ: itemPicture, pos: 40, val: "S9", line: 0},
+				{typ: itemDot, pos: 42, val: ".", line: 0},
+				{typ: itemEOL, pos: 43, val: "\n", line: 0},
+				{typ: itemEOF, pos: 44, val: "", line: 1},
+			},
+		}, {
+			name: "LowerCasePictureIsOccursTimes",
+			l: &lexer{
+				name:  "lexer",
+				input: "   05  1st-line picture is x(5), occurs 2 times.\n",
+				items: make([]item, 0),
+			},
+			want: []item{
+				{typ: itemSpace, pos: 0, val: "   ", line: 0},
+				{typ: itemNumber, pos: 3, val: "05", line: 0},
+				{typ: itemSpace, pos: 5, val: "  ", line: 0},
+				{typ: itemIdentifier, pos: 7, val: "1st-line", line: 0},
+				{typ: itemSpace, pos: 15, val: " ", line: 0},
+				{typ: itemPIC, pos: 16, val: "picture", line: 0},
+				{typ: itemSpace, pos: 23, val: " ", line: 0},
+				{typ: itemKeyword, pos: 24, val: "is", line: 0},
+				{typ: itemSpace, pos: 26, val: " ", line: 0},
+				{typ: itemPicture, pos: 27, val: "x(5)", line: 0},
+				{typ: itemChar, pos: 31, val: ",", line: 0},
+				{typ: itemSpace, pos: 32, val: " ", line: 0},
+				{typ: itemKeyword, pos: 33, val: "occurs", line: 0},
+				{typ: itemSpace, pos: 39, val: " ", line: 0},
+				{typ: itemNumber, pos: 40, val: "2", line: 0},
+				{typ: itemSpace, pos: 41, val: " ", line: 0},
+				{typ: itemKeyword, pos: 42, val: "times", line: 0},
+				{typ: itemDot, pos: 47, val: ".", line: 0},
+				{typ: itemEOL, pos: 48, val: "\n", line: 0},
+				{typ: itemEOF, pos: 49, val: "", line: 1},
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -204,3 +329,20 @@ func Test_lexer_run(t *testing.T) {
 		})
 	}
 }
+
+func Test_New_Logger(t *testing.T) {
+	input := "000600         10  X710203-STATEMENT-TYPE       PIC X.                  00000167\n"
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	_, err := NewTree(New("silent", input)).Parse()
+	require.NoError(t, err)
+	require.Empty(t, buf.String(), "nothing should be logged by default")
+
+	_, err = NewTree(New("verbose", input, WithLogger(log.New(&buf, "", 0)))).Parse()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "building new lexer")
+	require.Contains(t, buf.String(), "parsing lexer tokens")
+}
@@ -0,0 +1,77 @@
+package lex
+
+import (
+	"io/fs"
+)
+
+// Logger is what the lexer and parser report their progress to. A *log.Logger
+// is a Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Option configures a Lexer, and any Tree built from it.
+type Option func(*lexer)
+
+// WithLogger sets the Logger that the lexer, and any Tree built from it,
+// report their progress to. By default, nothing is logged.
+func WithLogger(lg Logger) Option {
+	return func(l *lexer) {
+		l.log = lg
+	}
+}
+
+// WithComments keeps the text of comment lines, which documents the entry that
+// follows them as its Record's Doc. By default, comments are skipped.
+func WithComments() Option {
+	return func(l *lexer) {
+		l.pre.comments = true
+	}
+}
+
+// WithDebugLines reads debugging lines, with D in the indicator area, as
+// source. By default, they're skipped like comments.
+func WithDebugLines() Option {
+	return func(l *lexer) {
+		l.pre.debug = true
+	}
+}
+
+// WithFormat sets the source format that copybooks are read in, which is
+// otherwise detected from the copybook itself. A >>SOURCE FORMAT directive
+// switches the format of the lines after it either way.
+func WithFormat(f Format) Option {
+	return func(l *lexer) {
+		l.pre.format = f
+	}
+}
+
+// WithCopybooks sets a library that the members named by COPY statements are
+// found in: fsys, in each of the directories dirs in turn, or its root if
+// there are none. Libraries set by more than one WithCopybooks are searched in
+// the order they're set. By default, there are none, so COPY statements fail.
+func WithCopybooks(fsys fs.FS, dirs ...string) Option {
+	return func(l *lexer) {
+		if len(dirs) == 0 {
+			dirs = []string{"."}
+		}
+
+		for _, dir := range dirs {
+			l.pre.libraries = append(l.pre.libraries, library{fsys: fsys, dir: dir})
+		}
+	}
+}
+
+// logf reports progress to the lexer's Logger, if it has one.
+func (l *lexer) logf(format string, v ...interface{}) {
+	if l.log != nil {
+		l.log.Printf(format, v...)
+	}
+}
+
+// logf reports progress to the tree's Logger, if it has one.
+func (t *Tree) logf(format string, v ...interface{}) {
+	if t.log != nil {
+		t.log.Printf(format, v...)
+	}
+}
@@ -2,274 +2,223 @@ package lex
 
 import (
 	"fmt"
-	"log"
 	"reflect"
 	"strings"
 )
 
-const (
-	picPrefix = "PIC "
-)
+// parseGroup parses the entries that follow a group item at the level, up to
+// the next entry at that level or above, into its children.
+//
+// In this way we may build a tree like
+//
+//	root
+//	 |-group1
+//	 |   |-picA
+//	 |-group2
+//	     |-picA
+//
+// The record description, at level 01, is the root itself, so the entries
+// subordinate to it are the root's children. Refer to README.md Level Number
+// section.
+func (t *Tree) parseGroup(group *Record, level int) error {
+	for t.idx < len(t.entries) {
+		d := t.entries[t.idx]
+		if d.rank() <= level {
+			return nil
+		}
 
-type parser func(t *Tree, l line, root *Record) *Record
+		t.idx++
+		switch {
+		case d.level == conditionNameLevel:
+			addValues(group, d)
+
+		case d.level == renamesLevel:
+			t.logf("RENAMES of entry on copybook line %d takes no storage, skipping", d.start.line)
+
+		case d.level == recordLevel && t.isGroup(group, d):
+			t.logf("record description on copybook line %d is the root, skipping", d.start.line)
+			if err := t.setUsage(group, group, d); err != nil {
+				return err
+			}
+
+		default:
+			rec, err := t.parseItem(group, d)
+			if err != nil {
+				return err
+			}
+
+			if err := t.add(group, rec, d); err != nil {
+				return err
+			}
+		}
+	}
 
-func noOp(t *Tree, l line, _ *Record) *Record {
-	log.Printf("%s on copybook line %d resulted in no-op", l.typ, t.lIdx)
 	return nil
 }
 
-// parsePIC is a parser that is used to build records
-// for lines that are determined to be PIC definitions
-func parsePIC(_ *Tree, l line, _ *Record) *Record {
-	picNumDef := strings.TrimPrefix(l.items[6].val, picPrefix)
-	length, err := parsePICCount(picNumDef)
-	if err != nil {
-		log.Fatalln(err)
+// parseItem builds the Record described by d, in the group, and those of the
+// entries subordinate to it, if it's a group.
+func (t *Tree) parseItem(group *Record, d *description) (*Record, error) {
+	rec := &Record{
+		Name:   d.name.val,
+		Occurs: d.occurs,
+		Filler: d.filler,
+		Doc:    d.doc,
 	}
 
-	return &Record{
-		depthMap: map[string]*Record{},
-		Name:     l.items[4].val,
-		Length:   length,
-		depth:    l.items[2].val,
-		Typ:      parsePICType(picNumDef),
+	if err := t.setUsage(group, rec, d); err != nil {
+		return nil, err
 	}
-}
 
-// parseRedefines is a parser that is used to build records
-// for lines that are determined to be REDEFINES definitions
-//
-// It will build the new Record and replace the the redefinition
-// target
-func parseRedefines(_ *Tree, l line, root *Record) *Record {
-	picNumDef := strings.TrimPrefix(l.items[10].val, picPrefix)
-	length, err := parsePICCount(picNumDef)
-	if err != nil {
-		log.Fatalln(err)
+	if t.isGroup(group, d) {
+		rec.Typ = reflect.Struct
+		return rec, t.parseGroup(rec, d.rank())
 	}
 
-	r := &Record{
-		depthMap: map[string]*Record{},
-		Name:     l.items[4].val,
-		Length:   length,
-		Typ:      parsePICType(picNumDef),
+	if t.hasSubordinates(d) {
+		next := t.entries[t.idx]
+		return nil, t.errorFor(next.start, fmt.Errorf("item %s has a PIC clause, so can't have subordinate items", d.name.val))
 	}
 
-	target := l.items[8].val
-	dst, i := root.fromCache(target)
-	if dst == nil {
-		log.Fatalln(fmt.Sprintf("redefinition target %s does not exist", target))
+	if d.picture.val == "" {
+		rec.Typ = rec.Usage.kind()
+		rec.Length = rec.Usage.size(0, 0)
+		return rec, nil
 	}
 
-	return root.redefine(i, dst, r)
-}
+	if !rec.Usage.hasPicture() {
+		return nil, t.errorFor(d.picture, fmt.Errorf("item %s of USAGE %s can't have a PIC clause", d.name.val, rec.Usage))
+	}
 
-// parseGroupRedefines is a parser that is used to build records
-// for lines that are determined to be REDEFINES definitions
-// for groups, instead of PICs
-//
-// It will build the new Record and replace the the redefinition
-// target
-func parseGroupRedefines(t *Tree, l line, root *Record) *Record {
-	target := strings.TrimSuffix(l.items[8].val, ".")
-	dst, _ := root.fromCache(target)
-	if dst == nil {
-		log.Fatalln(fmt.Sprintf("redefinition target %s does not exist", target))
+	pic, err := ParsePicture(d.picture.val)
+	if err != nil {
+		return nil, t.errorFor(d.picture, err)
 	}
 
-	if dst.depthMap == nil || len(dst.depthMap) == 0 {
-		// then check whether a node has children at this depth
-		parent, seenGroup := root.depthMap[dst.depth]
-		if seenGroup {
-			copyDepthMap(parent, dst)
-			root = parent
-		}
+	if u, ok := impliedUsages[pic.Category]; ok && rec.Usage == DisplayUsage {
+		rec.Usage = u
 	}
 
-	r := &Record{
-		Name:     l.items[4].val,
-		Typ:      reflect.Struct,
-		depth:    l.items[2].val,
-		depthMap: dst.depthMap,
+	if rec.Usage.numeric() && pic.Category != NumericCategory {
+		return nil, t.errorFor(d.picture, fmt.Errorf("item %s of USAGE %s must have a numeric PIC clause", d.name.val, rec.Usage))
 	}
 
-	dst, i := root.fromCache(target)
-	if dst == nil {
-		log.Fatalln(fmt.Sprintf("redefinition target %s does not exist", target))
+	rec.Picture, rec.Typ = pic, pic.Kind()
+	rec.Edited = pic.Category == NumericEditedCategory || d.separate
+	positions := pic.Size
+	if d.separate {
+		positions++
 	}
-	root.Length -= dst.Length
 
-	r = root.redefine(i, dst, r)
-	t.parseLines(r)
-	root.Length += r.Length
-	return r
+	rec.Length = rec.Usage.size(positions, pic.Digits)
+	return rec, nil
 }
 
-func parseOccurs(_ *Tree, l line, _ *Record) *Record {
-	picNumDef := strings.TrimPrefix(strings.TrimSpace(l.items[6].val), picPrefix)
-	length, err := parsePICCount(picNumDef)
-	if err != nil {
-		log.Fatalln(err)
+// isGroup reports whether d, in the group, describes a group item: one without
+// a PIC clause, unless it has a usage that takes none, and no subordinate
+// items.
+func (t *Tree) isGroup(group *Record, d *description) bool {
+	if d.picture.val != "" {
+		return false
 	}
 
-	n, err := parseOccursCount(l.items[8])
-	if err != nil {
-		log.Fatalln(err)
+	u := group.Usage
+	if d.usage.val != "" {
+		u = usages[strings.ToUpper(d.usage.val)]
 	}
 
-	return &Record{
-		Name:     l.items[4].val,
-		Length:   length,
-		Occurs:   n,
-		depth:    l.items[2].val,
-		depthMap: map[string]*Record{},
-		Typ:      parsePICType(picNumDef),
-	}
+	return u.hasPicture() || t.hasSubordinates(d)
 }
 
-// parseRedefinesMulti validates that the next line in the tree returns an
-// expected multiline redefines part from the trie, and matches that word.
-// After which, it concatenates the origin line items, and the items from the
-// subsequent line, to make a valid, single-line REDEFINES definition, that is
-// then parsed.
-func parseRedefinesMulti(t *Tree, l line, root *Record) *Record {
-	if err := t.nextLine(); err != nil {
-		panic(err)
-	}
-
-	_, i, ok := basicParserGet(t.line.items)
-	if !ok || !equalWord(getWord(i), multiRedefinesPartWord) {
-		log.Fatalln("parser indicated multi-line redefinition, but failed to verify next line")
+// hasSubordinates reports whether the entry after d, the next to parse, is
+// subordinate to it.
+func (t *Tree) hasSubordinates(d *description) bool {
+	if t.idx >= len(t.entries) {
+		return false
 	}
 
-	l.items = lineFromMultiRedefines(l.items, i)
-	nl := l
-
-	return parseRedefines(nil, nl, root)
+	next := t.entries[t.idx]
+	return next.level <= maxGroupLevel && next.rank() > d.rank()
 }
 
-// parseOccursMulti validates that the next line in the tree returns an expected
-// multiline occurs part from the trie, and matches that word.
-// After which, it concatenates the origin line items, and the items from the
-// subsequent line, to make a valid, single-line OCCURS definition, that is then
-// parsed.
-func parseOccursMulti(t *Tree, l line, _ *Record) *Record {
-	if err := t.nextLine(); err != nil {
-		panic(err)
+// setUsage sets the usage of rec, described by d, in the group, to that given
+// by its USAGE clause, or else to that of the group. An item can't have a
+// usage other than one given to its group.
+func (t *Tree) setUsage(group, rec *Record, d *description) error {
+	rec.Usage, rec.usageSet = group.Usage, group.usageSet
+	if d.usage.val == "" {
+		return nil
 	}
 
-	_, i, ok := basicParserGet(t.line.items)
-	if !ok || !equalWord(getWord(i), multiOccursPartWord) {
-		log.Fatalln()
+	u := usages[strings.ToUpper(d.usage.val)]
+	if group.usageSet && u != group.Usage {
+		return t.errorFor(d.usage, fmt.Errorf("USAGE %s conflicts with USAGE %s of the group", d.usage.val, group.Usage))
 	}
 
-	l.items = lineFromMultiOccurs(l.items, i)
-	nl := l
-
-	return parseOccurs(nil, nl, nil)
+	rec.Usage, rec.usageSet = u, true
+	return nil
 }
 
-// parseNumDelimitedStruct is a parser wrapper used to build records
-// for lines that are determined to be new group definitions that are built with
-// number delimiter tokens at the start and end of the source line
-//
-// It will call parseStruct to handle logic for new groups
-func parseNumDelimitedStruct(t *Tree, l line, root *Record) *Record {
-	// if the level number is 01, ignore this object.
-	// refer to README.md Level Number section
-	if l.items[2].val == recordDescriptionIndicator {
-		return noOp(t, l, root)
+// add adds the Record rec, described by d, to the group, in place of the item
+// it redefines, if any.
+func (t *Tree) add(group, rec *Record, d *description) error {
+	if d.redefines.val == "" {
+		group.add(rec)
+		return nil
 	}
 
-	return parseStruct(t, l, root, 4, 2)
-}
-
-// parseNonNumDelimitedStruct is a parser wrapper used to build records
-// for lines that are determined to be new group definitions that are built
-// without number delimiter tokens at the start and end of the source line
-//
-// It will call parseStruct to handle logic for new groups
-func parseNonNumDelimitedStruct(t *Tree, l line, root *Record) *Record {
-	// if the level number is 01, ignore this object.
-	// refer to README.md Level Number section
-	if l.items[1].val == recordDescriptionIndicator {
-		return noOp(t, l, root)
+	target := d.redefines.val
+	if _, i := group.fromCache(target); i >= 0 {
+		group.redefine(i, rec)
+		return nil
 	}
 
-	return parseStruct(t, l, root, 3, 1)
+	return t.errorFor(d.redefines, fmt.Errorf("redefinition target %s does not exist", target))
 }
 
-// parseStruct will build a new Record of type struct, store itself under the
-// parent Record as a child. It will also add an entry to the parent struct
-// indicating a new group.
-//
-// new groups are determined by a number token preceding an identifier token
-// if this number has been seen before, then this struct is not a new group, but
-// a sibling of other structs/pics in the root Record
-// if this number is a new unseen value, parsing will continue for each line
-// but each new line will treat this struct as it's root.
+// addValues adds the values of the condition name d to the item it belongs
+// to, which is the one before it: the last child of the group, or the group
+// itself if it has no children yet.
 //
-// In this way we may build a tree like
-// root
-//  |-group1
-//  |   |-picA
-//  |-group2
-//  |	|-picA
-func parseStruct(_ *Tree, l line, _ *Record, nameIdx, groupIdx int) *Record {
-	newNode := &Record{
-		Name:     l.items[nameIdx].val,
-		Typ:      reflect.Struct,
-		depth:    l.items[groupIdx].val,
-		depthMap: map[string]*Record{},
+// As ranges (VALUE 1 THRU 9) can't be represented as a list of values, any
+// item with a range condition has no values recorded at all.
+func addValues(group *Record, d *description) {
+	target := group
+	if len(group.Children) > 0 {
+		target = group.Children[len(group.Children)-1]
 	}
 
-	return newNode
-}
+	switch {
+	case target.valueless:
+		return
 
-func delve(t *Tree, root *Record, newRecord *Record) *Record {
-	parent, seenGroup := root.depthMap[newRecord.depth]
-	if seenGroup {
-		parent.Children = append(parent.Children, newRecord)
-		if newRecord.depthMap == nil || len(newRecord.depthMap) == 0 {
-			copyDepthMap(parent, newRecord)
-		}
-		t.parseLines(newRecord)
-		l := newRecord.Length
-		if newRecord.Occurs > 0 {
-			l *= newRecord.Occurs
-		}
+	case d.valueless:
+		target.Values = nil
+		target.valueless = true
 
-		parent.Length += l
-		// then the parent is the target for continued addition of children
-		return parent
+	default:
+		target.Values = append(target.Values, d.values...)
 	}
+}
 
-	if root.depthMap == nil || len(root.depthMap) == 0 {
-		root.depthMap = make(map[string]*Record)
-	}
-
-	root.depthMap[newRecord.depth] = root
-	copyDepthMap(root, newRecord)
-
-	root.Children = append(root.Children, newRecord)
-	t.parseLines(newRecord)
-
-	l := newRecord.Length
-	if newRecord.Occurs > 0 {
-		l *= newRecord.Occurs
+// rank returns where the entry d stands in the hierarchy of a record, which
+// is its level number, but for those that aren't part of a group. Independent
+// items stand with record descriptions, and condition names and RENAMES below
+// every other item.
+func (d *description) rank() int {
+	switch d.level {
+	case independentLevel:
+		return recordLevel
+	case renamesLevel, conditionNameLevel:
+		return conditionNameLevel
 	}
 
-	root.Length += l
-	// else the newRecord is the target for continued addition of children
-	return newRecord
+	return d.level
 }
 
-func copyDepthMap(src, dst *Record) {
-	if dst.depthMap == nil || len(dst.depthMap) == 0 {
-		dst.depthMap = make(map[string]*Record)
-	}
-
-	for key, value := range src.depthMap {
-		dst.depthMap[key] = value
-	}
+// unquote returns the value of a literal wrapped in apostrophes or quotes,
+// within which a doubled quote stands for one.
+func unquote(s string) string {
+	q := s[:1]
+	return strings.ReplaceAll(s[1:len(s)-1], q+q, q)
 }
@@ -0,0 +1,573 @@
+package lex
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Category is the class of data that a PICTURE character-string describes.
+type Category int
+
+const (
+	// AlphabeticCategory pictures hold letters and spaces, e.g. A(10).
+	AlphabeticCategory Category = iota + 1
+
+	// AlphanumericCategory pictures hold any characters, e.g. X(10) or A9X.
+	AlphanumericCategory
+
+	// AlphanumericEditedCategory pictures hold any characters, with spaces,
+	// zeros or slashes inserted, e.g. XXBXX or 99/99/99XX.
+	AlphanumericEditedCategory
+
+	// NumericCategory pictures hold a number, with an assumed decimal point
+	// and sign, e.g. S9(5)V99.
+	NumericCategory
+
+	// NumericEditedCategory pictures hold a number as it would be printed,
+	// e.g. $ZZ,ZZ9.99CR.
+	NumericEditedCategory
+
+	// FloatingPointCategory pictures hold a number as a signed mantissa and
+	// exponent, e.g. +9(3).99E+99.
+	FloatingPointCategory
+
+	// NationalCategory pictures hold UTF-16 characters, e.g. N(10).
+	NationalCategory
+
+	// NationalEditedCategory pictures hold UTF-16 characters, with spaces,
+	// zeros or slashes inserted, e.g. NNBNN.
+	NationalEditedCategory
+
+	// DBCSCategory pictures hold double-byte characters, e.g. G(10).
+	DBCSCategory
+)
+
+const (
+	// maxPictureLength is the most characters a PICTURE character-string may
+	// be written with.
+	maxPictureLength = 50
+
+	// maxDigits is the most digit positions, counting P scaling positions,
+	// that a number can have, with the ARITH(EXTEND) compiler option.
+	maxDigits = 31
+
+	// maxEditedSize is the most character positions a numeric-edited item
+	// can have.
+	maxEditedSize = 249
+
+	// pictureSymbols are the symbols that a PICTURE character-string can be
+	// made up of, besides CR and DB. The currency symbol is only ever $.
+	pictureSymbols = "ABEGNPSVXZ90/,.+-*$"
+
+	// insertionSymbols are the simple insertion symbols of edited pictures.
+	insertionSymbols = "B0/,"
+)
+
+var categoryNames = map[Category]string{
+	AlphabeticCategory: "alphabetic", AlphanumericCategory: "alphanumeric",
+	AlphanumericEditedCategory: "alphanumeric-edited", NumericCategory: "numeric",
+	NumericEditedCategory: "numeric-edited", FloatingPointCategory: "external floating-point",
+	NationalCategory: "national", NationalEditedCategory: "national-edited", DBCSCategory: "DBCS",
+}
+
+// String returns the name of the category.
+func (c Category) String() string {
+	return categoryNames[c]
+}
+
+// Picture describes the data that a PIC clause gives an item. Pictures are
+// made by ParsePicture, which applies the rules of IBM Enterprise COBOL.
+type Picture struct {
+	Category Category // class of data the picture describes
+	Digits   int      // digit positions, not counting P scaling positions
+	Scale    int      // digits after the assumed decimal point, negative for P scaling to its left
+	Signed   bool     // the item holds a sign
+	Scaling  int      // P positions, which scale the digits by a power of ten, but aren't stored
+	Size     int      // character positions of the item, were its USAGE DISPLAY
+
+	text string
+}
+
+// String returns the character-string that the picture was parsed from.
+func (p *Picture) String() string {
+	return p.text
+}
+
+// Kind returns the Go type that the data of the picture is decoded to.
+func (p *Picture) Kind() reflect.Kind {
+	switch p.Category {
+	case NumericCategory, NumericEditedCategory:
+		switch {
+		case p.Scale > 0:
+			return reflect.Float64
+		case p.Signed:
+			return reflect.Int
+		}
+
+		return reflect.Uint
+
+	case FloatingPointCategory:
+		return reflect.Float64
+	}
+
+	return reflect.String
+}
+
+// run is a symbol of a PICTURE character-string, and the number of times it
+// is repeated, as in X(10), or written out, as in XXX.
+type run struct {
+	symbol string
+	n      int
+}
+
+// ParsePicture parses the character-string of a PIC clause, e.g. S9(5)V99,
+// in any case.
+func ParsePicture(s string) (*Picture, error) {
+	s = strings.ToUpper(s)
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("empty PIC")
+	case len(s) > maxPictureLength:
+		return nil, fmt.Errorf("PIC %s is longer than %d characters", s, maxPictureLength)
+	case strings.HasSuffix(s, "."), strings.HasSuffix(s, ","):
+		return nil, fmt.Errorf("PIC %s can't end with %q", s, s[len(s)-1:])
+	}
+
+	runs, err := pictureRuns(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Picture{text: s}
+	symbols := countSymbols(runs)
+	switch {
+	case symbols["E"] > 0:
+		err = p.parseFloatingPoint(runs)
+	case symbols["G"] > 0:
+		err = p.parseCharacters(runs, symbols, "GB", DBCSCategory, DBCSCategory)
+	case symbols["N"] > 0:
+		err = p.parseCharacters(runs, symbols, "NB0/", NationalCategory, NationalEditedCategory)
+	case symbols["A"] > 0 || symbols["X"] > 0:
+		err = p.parseCharacters(runs, symbols, "AX9B0/", AlphanumericCategory, AlphanumericEditedCategory)
+		if err == nil && p.Category == AlphanumericCategory && symbols["X"] == 0 && symbols["9"] == 0 {
+			p.Category = AlphabeticCategory
+		}
+	case onlySymbols(symbols, "9PSV"):
+		err = p.parseNumeric(runs, symbols)
+	default:
+		err = p.parseNumericEdited(runs, symbols)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid PIC %s: %w", s, err)
+	}
+
+	return p, nil
+}
+
+// pictureRuns splits s into the runs of its symbols, merging those that
+// follow one another, so that 9(3)99 is a run of five 9s.
+func pictureRuns(s string) ([]run, error) {
+	var runs []run
+	for i := 0; i < len(s); {
+		symbol := s[i : i+1]
+		switch {
+		case strings.HasPrefix(s[i:], "CR"), strings.HasPrefix(s[i:], "DB"):
+			symbol = s[i : i+2]
+		case !strings.Contains(pictureSymbols, symbol):
+			return nil, fmt.Errorf("invalid symbol %q in PIC %s", symbol, s)
+		}
+
+		i += len(symbol)
+		n := 1
+		if i < len(s) && s[i] == leftParen {
+			right := strings.IndexByte(s[i:], rightParen)
+			if right < 0 {
+				return nil, fmt.Errorf("unterminated repetition in PIC %s", s)
+			}
+
+			amount, err := strconv.Atoi(s[i+1 : i+right])
+			if err != nil || amount < 1 {
+				return nil, fmt.Errorf("invalid repetition %s in PIC %s", s[i:i+right+1], s)
+			}
+
+			n = amount
+			i += right + 1
+		}
+
+		if len(runs) > 0 && runs[len(runs)-1].symbol == symbol {
+			runs[len(runs)-1].n += n
+			continue
+		}
+
+		runs = append(runs, run{symbol: symbol, n: n})
+	}
+
+	return runs, nil
+}
+
+// countSymbols returns the number of positions of each symbol of the runs.
+func countSymbols(runs []run) map[string]int {
+	symbols := make(map[string]int)
+	for _, r := range runs {
+		symbols[r.symbol] += r.n
+	}
+
+	return symbols
+}
+
+// onlySymbols reports whether every symbol counted is one of allowed.
+func onlySymbols(symbols map[string]int, allowed string) bool {
+	for symbol := range symbols {
+		if len(symbol) != 1 || !strings.Contains(allowed, symbol) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// positions returns the number of character positions that the runs take,
+// where CR and DB take two, and S, V and P none.
+func positions(runs []run) int {
+	size := 0
+	for _, r := range runs {
+		switch r.symbol {
+		case "S", "V", "P":
+		case "CR", "DB":
+			size += 2 * r.n
+		default:
+			size += r.n
+		}
+	}
+
+	return size
+}
+
+// parseCharacters parses a picture of characters, made of the allowed symbols,
+// which is of the edited category if it inserts B, 0 or /, and of the plain
+// category otherwise.
+func (p *Picture) parseCharacters(runs []run, symbols map[string]int, allowed string, plain, edited Category) error {
+	if !onlySymbols(symbols, allowed) {
+		return fmt.Errorf("%s pictures can only have %s", plain, describeSymbols(allowed))
+	}
+
+	p.Category = plain
+	if symbols["B"]+symbols["0"]+symbols["/"] > 0 {
+		p.Category = edited
+	}
+
+	p.Size = positions(runs)
+	return nil
+}
+
+// describeSymbols lists the symbols of s for an error message.
+func describeSymbols(s string) string {
+	symbols := strings.Split(s, "")
+	if len(symbols) == 1 {
+		return symbols[0]
+	}
+
+	return strings.Join(symbols[:len(symbols)-1], ", ") + " and " + symbols[len(symbols)-1]
+}
+
+// parseNumeric parses a picture of only 9, P, S and V symbols:
+//
+//	[S] { 9... [V 9...] | [V] P... 9... | 9... P... [V] }
+func (p *Picture) parseNumeric(runs []run, symbols map[string]int) error {
+	p.Category = NumericCategory
+	switch {
+	case symbols["S"] > 1:
+		return fmt.Errorf("S can only be used once")
+	case symbols["S"] == 1 && runs[0].symbol != "S":
+		return fmt.Errorf("S must be the leftmost symbol")
+	case symbols["V"] > 1:
+		return fmt.Errorf("V can only be used once")
+	case symbols["9"] == 0:
+		return fmt.Errorf("a numeric PIC must have a 9")
+	}
+
+	p.Signed = symbols["S"] == 1
+	p.Digits, p.Scaling = symbols["9"], symbols["P"]
+	if p.Digits+p.Scaling > maxDigits {
+		return fmt.Errorf("a number can have at most %d digits", maxDigits)
+	}
+
+	p.Size = positions(runs)
+	return p.setScale(runs, "9")
+}
+
+// setScale sets the scale of the picture from where its assumed decimal point,
+// a V or a period, and its P scaling positions are among its digit positions,
+// the runs of any symbol in digits.
+func (p *Picture) setScale(runs []run, digits string) error {
+	point, scaling := -1, -1
+	before, after := 0, 0 // digit positions before and after the point, or the P positions
+	for i, r := range runs {
+		switch {
+		case r.symbol == "V" || r.symbol == ".":
+			if point >= 0 {
+				return fmt.Errorf("there can only be one decimal point")
+			}
+
+			point = i
+		case r.symbol == "P":
+			if scaling >= 0 {
+				return fmt.Errorf("P positions must be together")
+			}
+
+			scaling = i
+		case strings.Contains(digits, r.symbol):
+			if scaling >= 0 {
+				after += r.n
+			} else {
+				before += r.n
+			}
+
+			if point >= 0 {
+				p.Scale += r.n
+			}
+		}
+	}
+
+	if scaling < 0 {
+		return nil
+	}
+
+	switch {
+	case before > 0 && after > 0:
+		return fmt.Errorf("P positions must be to the left or right of all digits")
+	case before > 0 && point >= 0 && point < scaling:
+		return fmt.Errorf("V can't come before P positions to the right of the digits")
+	case after > 0 && point > scaling:
+		return fmt.Errorf("V can't come after P positions to the left of the digits")
+	case before > 0:
+		p.Scale = -p.Scaling
+	default:
+		p.Scale = after + p.Scaling
+	}
+
+	return nil
+}
+
+// parseNumericEdited parses a picture of a number that's edited for printing,
+// with zero suppression, insertion characters, a currency symbol and a sign.
+func (p *Picture) parseNumericEdited(runs []run, symbols map[string]int) error { // nolint:gocyclo
+	p.Category = NumericEditedCategory
+	plus, minus, credit := symbols["+"], symbols["-"], symbols["CR"]+symbols["DB"]
+	switch {
+	case symbols["S"] > 0:
+		return fmt.Errorf("S can't be used with editing symbols")
+	case symbols["A"]+symbols["X"] > 0:
+		return fmt.Errorf("A and X can't be used with numeric editing symbols")
+	case plus > 0 && minus > 0:
+		return fmt.Errorf("+ and - can't be used together")
+	case credit > 0 && plus+minus > 0:
+		return fmt.Errorf("CR and DB can't be used with + or -")
+	case credit > 1:
+		return fmt.Errorf("CR or DB can only be used once")
+	case credit == 1 && !strings.Contains("CRDB", runs[len(runs)-1].symbol):
+		return fmt.Errorf("CR and DB must be the rightmost symbol")
+	case symbols["Z"] > 0 && symbols["*"] > 0:
+		return fmt.Errorf("Z and * can't be used together")
+	case symbols["V"]+symbols["."] > 1:
+		return fmt.Errorf("there can only be one decimal point")
+	}
+
+	p.Signed = plus+minus+credit > 0
+	floating, err := floatingSymbol(symbols)
+	if err != nil {
+		return err
+	}
+
+	for _, symbol := range []string{"$", "+", "-"} {
+		if symbol != floating && symbols[symbol] == 1 && !isFixedInsertion(runs, symbol) {
+			return fmt.Errorf("%s must be the leftmost or rightmost symbol", symbol)
+		}
+	}
+
+	if floating != "" {
+		if symbols["Z"]+symbols["*"] > 0 {
+			return fmt.Errorf("a floating %s can't be used with Z or *", floating)
+		}
+
+		if err := checkFloating(runs, floating); err != nil {
+			return err
+		}
+	} else if err := checkSuppression(runs); err != nil {
+		return err
+	}
+
+	p.Digits = symbols["9"] + symbols["Z"] + symbols["*"]
+	if floating != "" {
+		p.Digits += symbols[floating] - 1
+	}
+
+	p.Scaling = symbols["P"]
+	switch {
+	case p.Digits == 0:
+		return fmt.Errorf("a numeric-edited PIC must have a digit position")
+	case p.Digits+p.Scaling > maxDigits:
+		return fmt.Errorf("a number can have at most %d digits", maxDigits)
+	}
+
+	p.Size = positions(runs)
+	if p.Size > maxEditedSize {
+		return fmt.Errorf("a numeric-edited item can have at most %d positions", maxEditedSize)
+	}
+
+	return p.setScale(runs, "9Z*"+floating)
+}
+
+// floatingSymbol returns the symbol, $, + or -, that's repeated to float
+// over the leftmost digit positions of an edited picture, if any.
+func floatingSymbol(symbols map[string]int) (string, error) {
+	floating := ""
+	for _, symbol := range []string{"$", "+", "-"} {
+		if symbols[symbol] < 2 { // nolint:gomnd // a single symbol is fixed
+			continue
+		}
+
+		if floating != "" {
+			return "", fmt.Errorf("%s and %s can't both float", floating, symbol)
+		}
+
+		floating = symbol
+	}
+
+	return floating, nil
+}
+
+// isFixedInsertion reports whether the single symbol of the runs is where a
+// fixed insertion symbol can be: leftmost, or rightmost. A currency symbol
+// may follow a leftmost sign, or come before a rightmost one.
+func isFixedInsertion(runs []run, symbol string) bool {
+	first, last := 0, len(runs)-1
+	if symbol == "$" {
+		if isSign(runs[first].symbol) {
+			first++
+		}
+
+		if last > first && isSign(runs[last].symbol) {
+			last--
+		}
+	}
+
+	return runs[first].symbol == symbol || runs[last].symbol == symbol
+}
+
+// isSign reports whether symbol is a sign symbol of an edited picture.
+func isSign(symbol string) bool {
+	return symbol == "+" || symbol == "-" || symbol == "CR" || symbol == "DB"
+}
+
+// checkFloating checks that the floating symbol is only repeated over the
+// leftmost digit positions, with only insertion symbols, and the decimal
+// point, among them. Only a fixed sign or currency symbol may come before
+// it, and if it floats over the decimal point, no 9 may follow.
+func checkFloating(runs []run, floating string) error {
+	start := 0
+	if runs[0].symbol != floating && (runs[0].symbol == "$" || isSign(runs[0].symbol)) {
+		start = 1
+	}
+
+	if runs[start].symbol != floating {
+		return fmt.Errorf("a floating %s must be leftmost", floating)
+	}
+
+	end := start
+scan:
+	for i := start; i < len(runs); i++ {
+		switch symbol := runs[i].symbol; {
+		case symbol == floating:
+			end = i
+		case symbol != "." && symbol != "V" && !strings.Contains(insertionSymbols, symbol):
+			break scan
+		}
+	}
+
+	point := pointWithin(runs[start : end+1])
+	for _, r := range runs[end+1:] {
+		switch {
+		case r.symbol == floating:
+			return fmt.Errorf("a floating %s must be one string of symbols", floating)
+		case r.symbol == "9" && point:
+			return fmt.Errorf("a floating %s over the decimal point can't be followed by 9", floating)
+		}
+	}
+
+	return nil
+}
+
+// pointWithin reports whether the runs hold a decimal point.
+func pointWithin(runs []run) bool {
+	for _, r := range runs {
+		if r.symbol == "." || r.symbol == "V" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSuppression checks that no 9 comes before the zero suppression
+// symbols, Z or *, which replace leading zeros.
+func checkSuppression(runs []run) error {
+	nine := false
+	for _, r := range runs {
+		switch {
+		case r.symbol == "9":
+			nine = true
+		case nine && (r.symbol == "Z" || r.symbol == "*"):
+			return fmt.Errorf("%s can't come after 9", r.symbol)
+		}
+	}
+
+	return nil
+}
+
+// parseFloatingPoint parses an external floating-point picture:
+//
+//	{+|-} mantissa E {+|-} 99
+//
+// where the mantissa is of 9s and one decimal point, a period or V.
+func (p *Picture) parseFloatingPoint(runs []run) error {
+	p.Category, p.Signed = FloatingPointCategory, true
+	e := 0
+	for runs[e].symbol != "E" {
+		e++
+	}
+
+	switch {
+	case e == 0 || !isPlusOrMinus(runs[0]):
+		return fmt.Errorf("the mantissa must start with + or -")
+	case runs[e].n != 1 || len(runs) != e+3 || !isPlusOrMinus(runs[e+1]):
+		return fmt.Errorf("E must be followed by + or -, and 99")
+	case runs[e+2].symbol != "9" || runs[e+2].n != 2: // nolint:gomnd // two digit exponent
+		return fmt.Errorf("the exponent must be 99")
+	}
+
+	mantissa := runs[1:e]
+	symbols := countSymbols(mantissa)
+	if !onlySymbols(symbols, "9.V") {
+		return fmt.Errorf("the mantissa can only have 9s and a decimal point")
+	}
+
+	switch {
+	case symbols["."]+symbols["V"] != 1:
+		return fmt.Errorf("the mantissa must have one decimal point")
+	case symbols["9"] == 0 || symbols["9"] > 16: // nolint:gomnd // IBM limit
+		return fmt.Errorf("the mantissa must have 1 to 16 digits")
+	}
+
+	p.Digits = symbols["9"]
+	p.Size = positions(runs)
+	return p.setScale(mantissa, "9")
+}
+
+// isPlusOrMinus reports whether r is a single + or - symbol.
+func isPlusOrMinus(r run) bool {
+	return r.n == 1 && (r.symbol == "+" || r.symbol == "-")
+}
@@ -0,0 +1,156 @@
+package lex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParsePicture(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want Picture
+		kind reflect.Kind
+	}{
+		// alphanumeric and alphabetic
+		{in: "X", want: Picture{Category: AlphanumericCategory, Size: 1}, kind: reflect.String},
+		{in: "X(10)", want: Picture{Category: AlphanumericCategory, Size: 10}, kind: reflect.String},
+		{in: "x(3)", want: Picture{Category: AlphanumericCategory, Size: 3}, kind: reflect.String},
+		{in: "AAX9", want: Picture{Category: AlphanumericCategory, Size: 4}, kind: reflect.String},
+		{in: "A(5)", want: Picture{Category: AlphabeticCategory, Size: 5}, kind: reflect.String},
+		{in: "XXBXX", want: Picture{Category: AlphanumericEditedCategory, Size: 5}, kind: reflect.String},
+		{in: "99/99/99XX", want: Picture{Category: AlphanumericEditedCategory, Size: 10}, kind: reflect.String},
+		{in: "AB0A", want: Picture{Category: AlphanumericEditedCategory, Size: 4}, kind: reflect.String},
+
+		// numeric
+		{in: "9(5)", want: Picture{Category: NumericCategory, Digits: 5, Size: 5}, kind: reflect.Uint},
+		{in: "S99", want: Picture{Category: NumericCategory, Digits: 2, Signed: true, Size: 2}, kind: reflect.Int},
+		{in: "S9(3)V9(2)", want: Picture{Category: NumericCategory, Digits: 5, Scale: 2, Signed: true, Size: 5}, kind: reflect.Float64},
+		{in: "V99", want: Picture{Category: NumericCategory, Digits: 2, Scale: 2, Size: 2}, kind: reflect.Float64},
+		{in: "99V", want: Picture{Category: NumericCategory, Digits: 2, Size: 2}, kind: reflect.Uint},
+		{in: "9(31)", want: Picture{Category: NumericCategory, Digits: 31, Size: 31}, kind: reflect.Uint},
+		{in: "99PPP", want: Picture{Category: NumericCategory, Digits: 2, Scale: -3, Scaling: 3, Size: 2}, kind: reflect.Uint},
+		{in: "S99PPPV", want: Picture{Category: NumericCategory, Digits: 2, Scale: -3, Signed: true, Scaling: 3, Size: 2}, kind: reflect.Int},
+		{in: "PPP999", want: Picture{Category: NumericCategory, Digits: 3, Scale: 6, Scaling: 3, Size: 3}, kind: reflect.Float64},
+		{in: "SVPP9", want: Picture{Category: NumericCategory, Digits: 1, Scale: 3, Signed: true, Scaling: 2, Size: 1}, kind: reflect.Float64},
+
+		// numeric-edited
+		{in: "ZZ9", want: Picture{Category: NumericEditedCategory, Digits: 3, Size: 3}, kind: reflect.Uint},
+		{in: "99B99", want: Picture{Category: NumericEditedCategory, Digits: 4, Size: 5}, kind: reflect.Uint},
+		{in: "9(4).9(2)", want: Picture{Category: NumericEditedCategory, Digits: 6, Scale: 2, Size: 7}, kind: reflect.Float64},
+		{in: "ZZZ,ZZ9.99CR", want: Picture{Category: NumericEditedCategory, Digits: 8, Scale: 2, Signed: true, Size: 12}, kind: reflect.Float64},
+		{in: "999V99DB", want: Picture{Category: NumericEditedCategory, Digits: 5, Scale: 2, Signed: true, Size: 7}, kind: reflect.Float64},
+		{in: "**,**9.99", want: Picture{Category: NumericEditedCategory, Digits: 7, Scale: 2, Size: 9}, kind: reflect.Float64},
+		{in: "ZZ.ZZ", want: Picture{Category: NumericEditedCategory, Digits: 4, Scale: 2, Size: 5}, kind: reflect.Float64},
+		{in: "-ZZ9", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 4}, kind: reflect.Int},
+		{in: "ZZ9+", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 4}, kind: reflect.Int},
+		{in: "$ZZ,ZZ9.99", want: Picture{Category: NumericEditedCategory, Digits: 7, Scale: 2, Size: 10}, kind: reflect.Float64},
+		{in: "-$ZZ9", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 5}, kind: reflect.Int},
+		{in: "ZZ9$-", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 5}, kind: reflect.Int},
+		{in: "$$$,$$9.99", want: Picture{Category: NumericEditedCategory, Digits: 7, Scale: 2, Size: 10}, kind: reflect.Float64},
+		{in: "$$$.$$", want: Picture{Category: NumericEditedCategory, Digits: 4, Scale: 2, Size: 6}, kind: reflect.Float64},
+		{in: "+++9", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 4}, kind: reflect.Int},
+		{in: "+$$$9", want: Picture{Category: NumericEditedCategory, Digits: 3, Signed: true, Size: 5}, kind: reflect.Int},
+		{in: "ZZZPP", want: Picture{Category: NumericEditedCategory, Digits: 3, Scale: -2, Scaling: 2, Size: 3}, kind: reflect.Uint},
+
+		// external floating-point
+		{in: "+9(3).99E+99", want: Picture{Category: FloatingPointCategory, Digits: 5, Scale: 2, Signed: true, Size: 11}, kind: reflect.Float64},
+		{in: "-V9(5)E-99", want: Picture{Category: FloatingPointCategory, Digits: 5, Scale: 5, Signed: true, Size: 10}, kind: reflect.Float64},
+
+		// national and DBCS
+		{in: "N(10)", want: Picture{Category: NationalCategory, Size: 10}, kind: reflect.String},
+		{in: "NNBNN", want: Picture{Category: NationalEditedCategory, Size: 5}, kind: reflect.String},
+		{in: "G(4)", want: Picture{Category: DBCSCategory, Size: 4}, kind: reflect.String},
+		{in: "GGBGG", want: Picture{Category: DBCSCategory, Size: 5}, kind: reflect.String},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePicture(tt.in)
+			require.NoError(t, err)
+
+			tt.want.text = strings.ToUpper(tt.in)
+			require.Equal(t, &tt.want, got)
+			require.Equal(t, tt.kind, got.Kind())
+			require.Equal(t, tt.want.text, got.String())
+		})
+	}
+}
+
+func Test_ParsePicture_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: "empty PIC"},
+		{in: strings.Repeat("X", 51), want: "is longer than 50 characters"},
+		{in: "99.", want: `can't end with "."`},
+		{in: "X(3", want: "unterminated repetition"},
+		{in: "X(0)", want: "invalid repetition (0)"},
+		{in: "X(A)", want: "invalid repetition (A)"},
+		{in: "9K", want: `invalid symbol "K"`},
+
+		// numeric
+		{in: "9S", want: "S must be the leftmost symbol"},
+		{in: "SS9", want: "S can only be used once"},
+		{in: "9V9V9", want: "V can only be used once"},
+		{in: "S", want: "a numeric PIC must have a 9"},
+		{in: "PPP", want: "a numeric PIC must have a 9"},
+		{in: "9(32)", want: "a number can have at most 31 digits"},
+		{in: "9(30)PP", want: "a number can have at most 31 digits"},
+		{in: "9P9", want: "P positions must be to the left or right of all digits"},
+		{in: "P9P", want: "P positions must be together"},
+		{in: "99VPP", want: "V can't come before P positions"},
+		{in: "PPV99", want: "V can't come after P positions"},
+
+		// numeric-edited
+		{in: "S9(3).99", want: "S can't be used with editing symbols"},
+		{in: "+ZZ9-", want: "+ and - can't be used together"},
+		{in: "+9CR", want: "CR and DB can't be used with + or -"},
+		{in: "---,--9CR", want: "CR and DB can't be used with + or -"},
+		{in: "9CRDB", want: "CR or DB can only be used once"},
+		{in: "9CR9", want: "CR and DB must be the rightmost symbol"},
+		{in: "ZZ**9", want: "Z and * can't be used together"},
+		{in: "9.99V9", want: "there can only be one decimal point"},
+		{in: "9$9", want: "$ must be the leftmost or rightmost symbol"},
+		{in: "9+9", want: "+ must be the leftmost or rightmost symbol"},
+		{in: "$$++9", want: "$ and + can't both float"},
+		{in: "ZZ$$$9", want: "a floating $ can't be used with Z or *"},
+		{in: "9$$$", want: "a floating $ must be leftmost"},
+		{in: "$$9$", want: "a floating $ must be one string of symbols"},
+		{in: "$$.$$9", want: "a floating $ over the decimal point can't be followed by 9"},
+		{in: "99ZZ", want: "Z can't come after 9"},
+		{in: "BB0", want: "a numeric-edited PIC must have a digit position"},
+		{in: "B(250)9", want: "a numeric-edited item can have at most 249 positions"},
+
+		// characters
+		{in: "X(3)Z", want: "alphanumeric pictures can only have A, X, 9, B, 0 and /"},
+		{in: "NX", want: "national pictures can only have N, B, 0 and /"},
+		{in: "G0", want: "DBCS pictures can only have G and B"},
+
+		// external floating-point
+		{in: "9.9E+99", want: "the mantissa must start with + or -"},
+		{in: "+9.9E99", want: "E must be followed by + or -, and 99"},
+		{in: "+9.9E+9", want: "the exponent must be 99"},
+		{in: "+99E+99", want: "the mantissa must have one decimal point"},
+		{in: "+9Z.9E+99", want: "the mantissa can only have 9s and a decimal point"},
+		{in: "+.9(17)E+99", want: "the mantissa must have 1 to 16 digits"},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePicture(tt.in)
+			require.Nil(t, got)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -1,9 +1,8 @@
 package lex
 
 import (
-	"log"
 	"reflect"
-	"sync"
+	"strings"
 )
 
 type Record struct {
@@ -11,39 +10,72 @@ type Record struct {
 	Length   int
 	Occurs   int
 	Typ      reflect.Kind
+	Usage    Usage    // how the item's data is stored, which Length is the storage of
+	Picture  *Picture // PIC clause of an elementary item, if it has one
+	Values   []string // allowed values, captured from level 88 condition names
+	Edited   bool     // numeric data is edited for printing, or has a separate sign
+	Filler   bool     // FILLER or unnamed item, which can't be referenced
+	Doc      string   // text of the comment lines before the item, if kept
 	Children []*Record
 
-	depth    string
-	depthMap map[string]*Record
-	cache    sync.Map
+	cache     map[string]int // index of each named child
+	valueless bool           // a condition name used a range, so Values can't be trusted
+	usageSet  bool           // Usage is given by a USAGE clause, so the group's items have it too
 }
 
-// toCache returns a Record, just stored into or previously loaded from the cache
-func (r *Record) toCache(child *Record, idx int) *Record {
-	r.cache.Store(child.Name, idx)
-	return child
+const filler = "FILLER"
+
+// isFiller reports whether a data name denotes an anonymous item
+func isFiller(name string) bool {
+	return name == "" || strings.EqualFold(name, filler)
 }
 
-// fromCache loads a Record, by name, from the cache if present
-func (r *Record) fromCache(name string) (*Record, int) {
-	idx, ok := r.cache.Load(name)
-	if !ok {
-		return nil, 0
+// size returns the length of the item, times the number of times it occurs.
+func (r *Record) size() int {
+	if r.Occurs > 0 {
+		return r.Length * r.Occurs
 	}
 
-	i, ok := idx.(int)
-	if !ok {
-		log.Fatalln("failed to cast cache return value to integer")
+	return r.Length
+}
+
+// add appends the child to the children of r.
+func (r *Record) add(child *Record) {
+	r.Length += child.size()
+	r.Children = append(r.Children, child)
+	r.toCache(child, len(r.Children)-1)
+}
+
+// redefine replaces the child at the index i with the child that redefines
+// it, which may still be referred to by the name of either.
+func (r *Record) redefine(i int, child *Record) {
+	r.Length += child.size() - r.Children[i].size()
+	r.Children[i] = child
+	r.toCache(child, i)
+}
+
+// toCache stores the index of a named child in the cache. FILLERs can't be
+// referenced, and there may be many of them, so they must not collide in it.
+// Data names are the same whatever their case, so are cached in upper case.
+func (r *Record) toCache(child *Record, idx int) {
+	if child.Filler {
+		return
 	}
 
-	return r.Children[i], i
+	if r.cache == nil {
+		r.cache = make(map[string]int)
+	}
+
+	r.cache[strings.ToUpper(child.Name)] = idx
 }
 
-func (r *Record) redefine(i int, dst, src *Record) *Record {
-	r.cache.Delete(dst.Name)
-	dst.Name = src.Name
-	dst.Length = src.Length
-	dst.Typ = src.Typ
-	dst.depthMap = src.depthMap
-	return r.toCache(dst, i)
+// fromCache loads a Record, and its index, by name, from the cache if present,
+// or returns an index of -1.
+func (r *Record) fromCache(name string) (*Record, int) {
+	i, ok := r.cache[strings.ToUpper(name)]
+	if !ok {
+		return nil, -1
+	}
+
+	return r.Children[i], i
 }
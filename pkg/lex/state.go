@@ -2,15 +2,14 @@ package lex
 
 import (
 	"fmt"
-	"log"
+	"strings"
 	"unicode"
 )
 
 const (
 	substituteHex = '\U0000001A'
 	singleQuote   = rune(39) // nolint:gomnd // rune of '
-
-	alphaNumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+	doubleQuote   = '"'
 )
 
 func lexInsideStatement(l *lexer) stateFn { // nolint:gocyclo // good luck simplifying this
@@ -18,6 +17,9 @@ func lexInsideStatement(l *lexer) stateFn { // nolint:gocyclo // good luck simpl
 	case isEOL(r):
 		l.emit(itemEOL)
 
+	case r == eof && l.copyErr != nil:
+		return l.errorf("%v", l.copyErr)
+
 	case r == eof:
 		l.emit(itemEOF)
 		return nil
@@ -26,203 +28,163 @@ func lexInsideStatement(l *lexer) stateFn { // nolint:gocyclo // good luck simpl
 		l.backup()
 		return lexSpace
 
-	case r == picLeft:
-		// special look-ahead for "PIC" so we don't break l.backup().
-		if l.pos < Pos(len(l.input)) {
-			// Look for PIC
-			if (r < '0' || '9' < r) && l.peek() == 'I' && l.lookAhead(2) == 'C' { // nolint:gomnd // obvious meaning
-				return lexPIC
-			}
-
-			return lexIdentifier
-		}
-
-	case r == 'O':
-		return lexOCCURS(l)
-
-	case r == 'R':
-		return lexREDEFINES(l)
-
-	case r == '+' || r == '-' || ('0' <= r && r <= '9'):
+	case r == '+' || r == '-':
 		l.backup()
 		return lexNumber
 
 	case isAlphaNumeric(r):
 		l.backup()
-		return lexIdentifier
+		// a word of digits alone is a number, but a data name may start with
+		// them, e.g. 1ST-ADDRESS-LINE
+		if strings.Trim(l.peekWord(), "0123456789") == "" {
+			return lexNumber
+		}
+
+		return lexWord
 
 	case r == '.':
 		l.emit(itemDot)
 
-	case r == singleQuote:
+	case r == singleQuote, r == doubleQuote:
 		return lexEnum
 
+	case r == '*' && l.peek() == '>':
+		return lexComment
+
 	case r <= unicode.MaxASCII && unicode.IsPrint(r):
 		l.emit(itemChar)
 
 	case r == substituteHex:
-		log.Printf("found SUBSTITUTE rune")
+		l.logf("found SUBSTITUTE rune")
 		l.emit(itemEOF)
 
 	default:
 		e := fmt.Errorf("unrecognized character in action: %#U", r)
-		log.Println(e)
+		l.logf("%v", e)
 		return l.errorf(e.Error())
 	}
 
 	return lexInsideStatement(l)
 }
 
-func lexPIC(l *lexer) stateFn {
-	var r rune
-	for {
-		r = l.next()
-
-		if !isPICChar(r) {
-			// if after a pic character, we get a space it is likely
-			// there may be an OCCURS definition to follow, e.g.
-			// PIC X(10) OCCURS 12.
-			if isSpace(r) {
-				switch nx := l.peek(); {
-				case isPICChar(nx), isPICType(nx), nx == '.':
-					continue
-
-				default:
-					l.backup()
-					l.emit(itemPIC)
-					return lexSpace(l)
-				}
-			}
-
-			// if we've just reached the terminator '.'
-			// let's peek and see if it's actually an explicit decimal point
-			// e.g. PIC 9(9).9(2) -> 123456789.50
-			if isPICChar(l.peek()) {
-				continue
-			}
-
-			if !l.atPICTerminator() {
-				l.backup()
-				break
-			}
-		}
-	}
-
-	if !l.atPICTerminator() {
+// lexWord scans a whole word before telling what it is: a reserved word,
+// whatever its case, or else a name.
+func lexWord(l *lexer) stateFn {
+	l.pos += Pos(len(l.peekWord()))
+	if !l.atTerminator() {
+		r := l.next()
 		e := fmt.Errorf("bad character %#U", r)
-		log.Println(e)
+		l.logf("%v", e)
 		return l.errorf(e.Error())
 	}
 
-	l.emit(itemPIC)
-	return lexInsideStatement(l)
-}
+	switch w := strings.ToUpper(l.input[l.start:l.pos]); {
+	case w == "PIC", w == "PICTURE":
+		l.emit(itemPIC)
+		return lexPicture
+
+	case isReserved(w):
+		l.emit(itemKeyword)
 
-func lexREDEFINES(l *lexer) stateFn {
-	if l.scanRedefines() {
-		l.emit(itemREDEFINES)
+	default:
+		l.emit(itemIdentifier)
 	}
 
-	return lexInsideStatement(l)
+	return lexInsideStatement
 }
 
-func lexOCCURS(l *lexer) stateFn {
-	if l.scanOccurs() {
-		l.emit(itemOCCURS)
-	}
+// lexPicture scans the character-string of a PIC clause, and the spaces and
+// IS that may come between it and the keyword. The character-string runs up to
+// the next space, but for a period, comma or semicolon before it, which is a
+// separator rather than part of it, e.g. PIC 9(9).9(2). or PIC X, VALUE 'Y'.
+func lexPicture(l *lexer) stateFn {
+	switch r := l.peek(); {
+	case isSpace(r):
+		for isSpace(l.peek()) {
+			l.next()
+		}
 
-	return lexInsideStatement(l)
-}
+		l.emit(itemSpace)
+		return lexPicture
 
-func (l *lexer) scanRedefines() bool {
-	l.acceptRun("REDEFINES")
-	if !isSpace(l.peek()) {
+	case isEOL(r):
 		l.next()
-		return false
-	}
+		l.emit(itemEOL)
+		return lexPicture
 
-	return true
-}
+	case r == eof:
+		return lexInsideStatement
+	}
 
-func (l *lexer) scanOccurs() bool {
-	l.acceptRun("OCCURS")
-	if !isSpace(l.peek()) {
-		l.next()
-		return false
+	if w := l.peekWord(); strings.EqualFold(w, "IS") {
+		l.pos += Pos(len(w))
+		l.emit(itemKeyword)
+		return lexPicture
 	}
 
 	for {
 		r := l.next()
-		if !isPICChar(r) && !isSpace(r) {
-			if l.atTerminator() {
-				l.backup()
-				break
-			}
-
-			panic(fmt.Sprintf("bad character %#U", r))
+		if r == eof || isSpace(r) || isEOL(r) || isPunctuation(r) && l.atWordEnd() {
+			l.backup()
+			break
 		}
 	}
 
-	return true
+	if l.pos > l.start {
+		l.emit(itemPicture)
+	}
+
+	return lexInsideStatement
 }
 
-func (l *lexer) atPICTerminator() bool {
+// atWordEnd reports whether the input is at the space, end of line or end of
+// input that follows a word.
+func (l *lexer) atWordEnd() bool {
 	r := l.peek()
-	return r == picRight
+	return isSpace(r) || isEOL(r) || r == eof
 }
 
-// lexIdentifier scans an alphanumeric.
-func lexIdentifier(l *lexer) stateFn {
-Loop:
-	for {
-		switch r := l.next(); {
-		case isAlphaNumeric(r):
-			// absorb.
-		default:
-			l.backup()
-			word := l.input[l.start:l.pos]
-			if !l.atTerminator() {
-				e := fmt.Errorf("bad character %#U", r)
-				log.Println(e)
-				return l.errorf(e.Error())
-			}
-
-			switch {
-			case word == "true", word == "false":
-				l.emit(itemBool)
-
-			default:
-				l.emit(itemIdentifier)
-			}
-
-			break Loop
-		}
+// peekWord returns, but does not consume, the rest of the word at the current
+// position in the input.
+func (l *lexer) peekWord() string {
+	i := int(l.pos)
+	for i < len(l.input) && isAlphaNumeric(rune(l.input[i])) {
+		i++
 	}
 
-	return lexInsideStatement(l)
+	return l.input[l.pos:i]
 }
 
-// lexEnum scans an apostrophe-wrapped alphanumeric value.
+// lexEnum scans a literal wrapped in apostrophes or quotes, having already
+// consumed the opening one. Within it, a doubled quote stands for one.
 func lexEnum(l *lexer) stateFn {
+	q := rune(l.input[l.start])
 	for {
-		switch r := l.peek(); {
-		case isAlphaNumeric(r):
-			// absorb
-			l.acceptRun(alphaNumeric)
-		default:
-			if !l.atEnumTerminator() {
-				e := fmt.Errorf("bad character %#U", r)
-				log.Println(e)
-				return l.errorf(e.Error())
-			}
-
+		switch r := l.next(); {
+		case r == q && l.peek() == q:
 			l.next()
+
+		case r == q:
 			l.emit(itemEnum)
 			return lexInsideStatement(l)
+
+		case r == eof, isEOL(r):
+			return l.errorf("literal is not terminated")
 		}
 	}
 }
 
+// lexComment scans a comment, from *> to the end of the line, having already
+// consumed the *.
+func lexComment(l *lexer) stateFn {
+	for r := l.peek(); r != eof && !isEOL(r); r = l.peek() {
+		l.next()
+	}
+
+	l.emit(itemComment)
+	return lexInsideStatement(l)
+}
+
 // lexNumber scans a number: decimal, octal, hex, float, or imaginary. This
 // isn't a perfect number scanner - for instance it accepts "." and "0x0.2"
 // and "089" - but when it's wrong the input is invalid and the parser (via
@@ -266,7 +228,9 @@ func (l *lexer) scanNumber() bool { // nolint:gocyclo // good luck simplifying t
 	}
 
 	l.acceptRun(digits)
-	if l.accept(".") {
+	// a '.' followed by anything but a digit ends the entry, e.g. VALUE 1.
+	if l.peek() == '.' && unicode.IsDigit(l.lookAhead(2)) {
+		l.next()
 		l.acceptRun(digits)
 	}
 
@@ -323,11 +287,6 @@ func (l *lexer) atTerminator() bool {
 	return false
 }
 
-func (l *lexer) atEnumTerminator() bool {
-	r := l.peek()
-	return r == singleQuote
-}
-
 // isSpace reports whether r is a space character.
 func isSpace(r rune) bool {
 	return r == ' ' || r == '\t'
@@ -343,12 +302,7 @@ func isAlphaNumeric(r rune) bool {
 	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
-func isPICChar(r rune) bool {
-	_, ok := picChars[r]
-	return ok || unicode.IsNumber(r)
-}
-
-func isPICType(r rune) bool {
-	_, ok := picTypes[r]
-	return ok
+// isPunctuation reports whether r is a separator when followed by a space.
+func isPunctuation(r rune) bool {
+	return r == '.' || r == ',' || r == ';'
 }
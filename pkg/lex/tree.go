@@ -2,133 +2,142 @@ package lex
 
 import (
 	"errors"
-	"io"
-	"log"
 	"reflect"
+	"strings"
 )
 
 type Tree struct {
-	lex   Lexer
-	token item
-	lines []line
-	state *Record
-	line  line
-	lIdx  int
+	name    string
+	lex     Lexer
+	log     Logger
+	token   item
+	entries []*description
+	state   *Record
+	idx     int // index of the next entry to parse
 }
 
 func NewTree(lxr Lexer) *Tree {
-	log.Println("building new tree")
-	root := &Record{Typ: reflect.Struct, Name: lxr.getName(), depthMap: make(map[string]*Record)}
-	return &Tree{
+	root := &Record{Typ: reflect.Struct, Name: lxr.getName()}
+	t := &Tree{
+		name:  lxr.getName(),
 		lex:   lxr,
+		log:   lxr.logger(),
 		state: root,
-		lIdx:  -1,
 	}
-}
 
-func (t *Tree) Parse() *Record {
-	log.Println("parsing lexer tokens")
-	for {
-		li := t.scanLine()
-		if t.token.typ == itemEOF {
-			log.Println("reached EOF token, input lexed.")
-			break
-		}
-
-		l := buildLine(li)
-
-		if l == nil {
-			continue
-		}
+	t.logf("building new tree")
+	return t
+}
 
-		t.lines = append(t.lines, *l)
+// Parse builds a Record from the lexed copybook. If the copybook can't be
+// parsed, it returns an *Error describing where and why.
+func (t *Tree) Parse() (*Record, error) {
+	t.logf("parsing lexer tokens")
+	entries, err := t.scanEntries()
+	if err != nil {
+		return nil, err
 	}
 
-	t.parseLines(t.state)
-	return t.state
-}
-
-func (t *Tree) scanLine() []item {
-	var lineItems []item
-	for {
-		t.token = t.next()
-		if t.token == (item{}) || t.token.typ == itemError {
-			break
+	for _, e := range entries {
+		d, err := t.parseEntry(e)
+		if err != nil {
+			return nil, err
 		}
 
-		if t.token.typ == itemEOL || itemEOF == t.token.typ {
-			break
-		}
+		t.entries = append(t.entries, d)
+	}
 
-		lineItems = append(lineItems, t.token)
+	if err := t.parseGroup(t.state, 0); err != nil {
+		return nil, err
 	}
 
-	return lineItems
+	return t.state, nil
 }
 
-// next returns the next token.
-func (t *Tree) next() item {
-	return t.lex.getNext()
-}
+// scanEntries splits the lexed copybook into its data description entries,
+// each documented by the comment lines before it.
+func (t *Tree) scanEntries() ([]entry, error) {
+	var (
+		entries []entry
+		cur     entry
+		doc     []string
+	)
 
-// parseLines generates the text for the line
-// and adds it to the tree data
-func (t *Tree) parseLines(root *Record) { //nolint:gocyclo // TODO: refine
 	for {
-		if errors.Is(t.nextLine(), io.EOF) {
-			break
+		li, err := t.scanLine()
+		if err != nil {
+			return nil, err
 		}
 
-		switch t.line.typ {
-		case lineJunk, lineEnum:
-			log.Printf("%s on copybook line %d resulted in no-op", t.line.typ, t.lIdx)
-			continue
+		li, text := splitComment(li)
+		if isBlank(li) && text != "" && len(cur.items) == 0 {
+			doc = append(doc, text)
+		}
 
-		case lineRedefines, lineMultilineRedefines, lineGroupRedefines:
-			t.line.fn(t, t.line, root)
+		// compiler-directing statements between entries only direct the listing
+		if len(cur.items) == 0 && isDirective(li) {
+			t.logf("skipping compiler-directing statement")
+			li = nil
+		}
 
-		case lineStruct:
-			rec := t.line.fn(t, t.line, root)
-			if rec == nil {
+		for _, it := range li {
+			switch {
+			case isSeparator(it):
 				continue
-			}
 
-			parent, ok := root.depthMap[rec.depth]
-			if ok {
-				root = parent
-			}
-
-			delve(t, root, rec)
+			case it.typ == itemDot && len(cur.items) == 0:
+				return nil, t.errorFor(it, errors.New("period ends an empty entry"))
 
-		default:
-			rec := t.line.fn(t, t.line, root)
-			if rec == nil {
-				log.Fatalf("parser returned nil record for line: %+v", t.line)
-			}
+			case it.typ == itemDot:
+				entries = append(entries, cur)
+				cur = entry{}
 
-			parent, ok := root.depthMap[rec.depth]
-			if ok {
-				root = parent
-			}
+			default:
+				if len(cur.items) == 0 {
+					cur.doc = strings.Join(doc, "\n")
+					doc = nil
+				}
 
-			idx := len(root.Children)
-			l := rec.Length
-			if rec.Occurs > 0 {
-				l *= rec.Occurs
+				cur.items = append(cur.items, it)
 			}
+		}
 
-			root.Length += l
-			root.Children = append(root.Children, root.toCache(rec, idx))
+		if t.token.typ == itemEOF || t.token == (item{}) {
+			t.logf("reached EOF token, input lexed.")
+			break
 		}
 	}
+
+	if len(cur.items) > 0 {
+		return nil, t.errorFor(cur.items[len(cur.items)-1], errors.New("entry is not terminated by a period"))
+	}
+
+	return entries, nil
 }
 
-func (t *Tree) nextLine() error {
-	if t.lIdx == len(t.lines)-1 {
-		return io.EOF
+func (t *Tree) scanLine() ([]item, error) {
+	var lineItems []item
+	for {
+		t.token = t.next()
+		if t.token == (item{}) {
+			break
+		}
+
+		if t.token.typ == itemError {
+			return nil, t.errorFor(t.token, errors.New(t.token.val))
+		}
+
+		if t.token.typ == itemEOL || itemEOF == t.token.typ {
+			break
+		}
+
+		lineItems = append(lineItems, t.token)
 	}
 
-	t.lIdx++
-	t.line = t.lines[t.lIdx]
-	return nil
+	return lineItems, nil
+}
+
+// next returns the next token.
+func (t *Tree) next() item {
+	return t.lex.getNext()
 }
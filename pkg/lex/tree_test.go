@@ -1,68 +1,57 @@
 package lex
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-func Test_parseLines(t *testing.T) {
-	root := &Record{Typ: reflect.Struct, Name: "root", depthMap: make(map[string]*Record)}
+func Test_parseGroup(t *testing.T) {
+	root := &Record{Typ: reflect.Struct, Name: "root"}
 	tree := &Tree{
-		lIdx: -1,
-		lines: []line{
+		entries: []*description{
 			{
-				typ: lineStruct,
-				fn:  parseNumDelimitedStruct,
-				items: []item{
-					{typ: itemNumber, pos: 0, val: "000160", line: 0},
-					{typ: itemSpace, pos: 6, val: "         ", line: 0},
-					{typ: itemNumber, pos: 15, val: "05", line: 0},
-					{typ: itemSpace, pos: 17, val: "  ", line: 0},
-					{typ: itemIdentifier, pos: 19, val: "DUMMY-GROUP-1", line: 0},
-					{typ: itemDot, pos: 32, val: ".", line: 0},
-					{typ: itemSpace, pos: 33, val: "                  ", line: 0},
-					{typ: itemNumber, pos: 51, val: "00000115", line: 0},
-					{typ: itemEOL, pos: 59, val: "\n", line: 0},
-				},
+				level: 5,
+				name:  item{typ: itemIdentifier, val: "DUMMY-GROUP-1"},
 			}, {
-				typ: linePIC,
-				fn:  parsePIC,
-				items: []item{
-					{typ: itemNumber, pos: 0, val: "000600", line: 1},
-					{typ: itemSpace, pos: 6, val: "         ", line: 1},
-					{typ: itemNumber, pos: 15, val: "10", line: 1},
-					{typ: itemSpace, pos: 17, val: "  ", line: 1},
-					{typ: itemIdentifier, pos: 19, val: "DUMMY-GROUP-1-OBJECT-A", line: 1},
-					{typ: itemSpace, pos: 41, val: "       ", line: 1},
-					{typ: itemPIC, pos: 48, val: "PIC X.", line: 1},
-					{typ: itemSpace, pos: 54, val: "                  ", line: 1},
-					{typ: itemNumber, pos: 72, val: "00000167", line: 1},
-					{typ: itemEOL, pos: 80, val: "\n", line: 1},
-				},
+				level:   10,
+				name:    item{typ: itemIdentifier, val: "DUMMY-GROUP-1-OBJECT-A"},
+				picture: item{typ: itemPicture, val: "X"},
 			}, {
-				typ: lineStruct,
-				fn:  parseNonNumDelimitedStruct,
-				items: []item{
-					{typ: itemSpace, pos: 6, val: "         ", line: 2},
-					{typ: itemNumber, pos: 15, val: "05", line: 2},
-					{typ: itemSpace, pos: 17, val: "  ", line: 2},
-					{typ: itemIdentifier, pos: 19, val: "DUMMY-GROUP-2", line: 2},
-					{typ: itemDot, pos: 32, val: ".", line: 2},
-					{typ: itemSpace, pos: 33, val: "                  ", line: 2},
-					{typ: itemEOL, pos: 59, val: "\n", line: 2},
-					{typ: itemEOF, pos: 60, val: "", line: 3},
-				},
+				level:  88,
+				name:   item{typ: itemIdentifier, val: "DUMMY-GROUP-1-OBJECT-A-YES"},
+				values: []string{"Y"},
+			}, {
+				level: 5,
+				name:  item{typ: itemIdentifier, val: "DUMMY-GROUP-2"},
 			},
 		},
 		state: root,
 	}
 
-	tree.parseLines(tree.state)
-	log.Println(tree.state)
+	require.NoError(t, tree.parseGroup(tree.state, 0))
+	deepCompare(t, &Record{
+		Name:   "root",
+		Typ:    reflect.Struct,
+		Length: 1,
+		Children: []*Record{{
+			Name:   "DUMMY-GROUP-1",
+			Typ:    reflect.Struct,
+			Length: 1,
+			Children: []*Record{{
+				Name:   "DUMMY-GROUP-1-OBJECT-A",
+				Typ:    reflect.String,
+				Length: 1,
+				Values: []string{"Y"},
+			}},
+		}, {
+			Name: "DUMMY-GROUP-2",
+			Typ:  reflect.Struct,
+		}},
+	}, tree.state)
 }
 
 func Test_Parse(t *testing.T) {
@@ -105,10 +94,10 @@ func Test_Parse(t *testing.T) {
 			in: NewTree(
 				New("test",
 					`000160     05  DUMMY-GROUP-1.                                           00000115
-000170         10  DUMMY-SUB-GROUP-1.                                00000116
-000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9.               00000117
-000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.               00000118
-000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9.               00000119
+000170         10  DUMMY-SUB-GROUP-1.                                   00000116
+000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9.                  00000117
+000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.                  00000118
+000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9.                  00000119
 		`)),
 		}, {
 			name: "RedefinesWithParentheses",
@@ -225,10 +214,10 @@ func Test_Parse(t *testing.T) {
 			},
 			in: NewTree(
 				New("test",
-					`000160     05  DUMMY-GROUP-1.                             00000115
-000170         10  DUMMY-SUB-GROUP-1.                        00000116
-000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9        00000117
-001300             OCCURS 12.                                00000242
+					`000160     05  DUMMY-GROUP-1.                                           00000115
+000170         10  DUMMY-SUB-GROUP-1.                                   00000116
+000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9                   00000117
+001300             OCCURS 12.                                           00000242
 `)),
 		}, {
 			name: "ExampleData",
@@ -386,6 +375,7 @@ func Test_Parse(t *testing.T) {
 							Name:   "DUMMY-GROUP-1-OBJECT-A",
 							Typ:    reflect.Float64,
 							Length: 12,
+							Edited: true,
 						}, {
 							Name:   "DUMMY-GROUP-1-OBJECT-B",
 							Typ:    reflect.String,
@@ -394,6 +384,7 @@ func Test_Parse(t *testing.T) {
 							Name:   "DUMMY-GROUP-1-OBJECT-C",
 							Typ:    reflect.Float64,
 							Length: 9,
+							Edited: true,
 						}},
 					}},
 				}},
@@ -401,19 +392,19 @@ func Test_Parse(t *testing.T) {
 			in: NewTree(
 				New("test",
 					`000160     05  DUMMY-GROUP-1.                                           00000115
-000170         10  DUMMY-SUB-GROUP-1.                                00000116
-000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9(9).9(2).       00000117
-000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.               00000118
-000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9(4).9(4).       00000119
+000170         10  DUMMY-SUB-GROUP-1.                                   00000116
+000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9(9).9(2).          00000117
+000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.                  00000118
+000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9(4).9(4).          00000119
 		`)),
 		}, {
 			name: "Skip88Enums",
 			in: NewTree(
 				New("test",
-					`001890         10  REGULAR-OBJECT       PIC 9(11).              00000375
-001900         10  EXAMPLE-BOOL-ENUM    PIC X.                  00000376
-001910             88  ENUM-FALSE    VALUE 'N'.              00000377
-001920             88  ENUM-TRUE    VALUE 'Y'.              00000378
+					`001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  EXAMPLE-BOOL-ENUM    PIC X.                          00000376
+001910             88  ENUM-FALSE    VALUE 'N'.                         00000377
+001920             88  ENUM-TRUE    VALUE 'Y'.                          00000378
 `)),
 			want: &Record{
 				Name:   "test",
@@ -428,19 +419,204 @@ func Test_Parse(t *testing.T) {
 						Name:   "EXAMPLE-BOOL-ENUM",
 						Typ:    reflect.String,
 						Length: 1,
+						Values: []string{"N", "Y"},
 					},
 				},
 			},
+		}, {
+			name: "88EnumValues",
+			in: NewTree(
+				New("test",
+					`001900         10  ACCOUNT-STATUS       PIC X.                          00000376
+001910             88  ACCOUNT-VALID    VALUES 'A' 'C' 'D'.             00000377
+001920         10  ACCOUNT-TYPE         PIC 9.                          00000378
+001930             88  ACCOUNT-BASIC    VALUE 1.                        00000379
+001940             88  ACCOUNT-PREMIUM  VALUE 2 3.                      00000380
+001950         10  ACCOUNT-TIER         PIC 9.                          00000381
+001960             88  ACCOUNT-LOW      VALUE 1 THRU 4.                 00000382
+001970             88  ACCOUNT-HIGH     VALUE 9.                        00000383
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 3,
+				Children: []*Record{
+					{
+						Name:   "ACCOUNT-STATUS",
+						Typ:    reflect.String,
+						Length: 1,
+						Values: []string{"A", "C", "D"},
+					}, {
+						Name:   "ACCOUNT-TYPE",
+						Typ:    reflect.Uint,
+						Length: 1,
+						Values: []string{"1", "2", "3"},
+					}, {
+						Name:   "ACCOUNT-TIER",
+						Typ:    reflect.Uint,
+						Length: 1,
+					},
+				},
+			},
+		}, {
+			name: "88OnGroup",
+			in: NewTree(
+				New("test",
+					`001900         10  ACCOUNT-CODE.                                        00000376
+001910             88  ACCOUNT-CLOSED   VALUE 'XX00'.                   00000377
+001920             15  ACCOUNT-PREFIX   PIC XX.                         00000378
+001930             15  ACCOUNT-NUMBER   PIC 99.                         00000379
+001940                 88  ACCOUNT-NONE VALUE 0.                        00000380
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 4,
+				Children: []*Record{{
+					Name:   "ACCOUNT-CODE",
+					Typ:    reflect.Struct,
+					Length: 4,
+					Values: []string{"XX00"},
+					Children: []*Record{
+						{Name: "ACCOUNT-PREFIX", Typ: reflect.String, Length: 2},
+						{Name: "ACCOUNT-NUMBER", Typ: reflect.Uint, Length: 2, Values: []string{"0"}},
+					},
+				}},
+			},
+		}, {
+			name: "NumericEdited",
+			in: NewTree(
+				New("test",
+					`000180         10  EDITED-A   PIC ZZZ,ZZ9.99CR.                         00000117
+000190         10  EDITED-B   PIC $**,**9.99-.                          00000118
+000200         10  EDITED-C   PIC ZZZ9.                                 00000119
+000210         10  EDITED-D   PIC +Z(4)9.                               00000120
+000220         10  EDITED-E   PIC 99/99/99.                             00000121
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 41,
+				Children: []*Record{{
+					Name:   "EDITED-A",
+					Typ:    reflect.Float64,
+					Length: 12,
+					Edited: true,
+				}, {
+					Name:   "EDITED-B",
+					Typ:    reflect.Float64,
+					Length: 11,
+					Edited: true,
+				}, {
+					Name:   "EDITED-C",
+					Typ:    reflect.Uint,
+					Length: 4,
+					Edited: true,
+				}, {
+					Name:   "EDITED-D",
+					Typ:    reflect.Int,
+					Length: 6,
+					Edited: true,
+				}, {
+					Name:   "EDITED-E",
+					Typ:    reflect.Uint,
+					Length: 8,
+					Edited: true,
+				}},
+			},
+		}, {
+			name: "SignSeparate",
+			in: NewTree(
+				New("test",
+					`000180         10  SIGNED-A   PIC S9(7)V99 SIGN LEADING SEPARATE.       00000117
+000190     10  SIGNED-B PIC S9(4) SIGN IS TRAILING SEPARATE CHARACTER.  00000118
+000200         10  SIGNED-C   PIC S9(4) SIGN TRAILING.                  00000119
+000210         10  SIGNED-D   PIC S9(4).                                00000120
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 23,
+				Children: []*Record{{
+					Name:   "SIGNED-A",
+					Typ:    reflect.Float64,
+					Length: 10,
+					Edited: true,
+				}, {
+					Name:   "SIGNED-B",
+					Typ:    reflect.Int,
+					Length: 5,
+					Edited: true,
+				}, {
+					Name:   "SIGNED-C",
+					Typ:    reflect.Int,
+					Length: 4,
+				}, {
+					Name:   "SIGNED-D",
+					Typ:    reflect.Int,
+					Length: 4,
+				}},
+			},
+		}, {
+			name: "Fillers",
+			in: NewTree(
+				New("test",
+					`000160     05  DUMMY-GROUP-1.                                           00000115
+000180         10  DUMMY-GROUP-1-OBJECT-A   PIC X(3).                   00000117
+000190         10  FILLER                   PIC X(2).                   00000118
+000200         10  DUMMY-GROUP-1-OBJECT-B   PIC X(3).                   00000119
+000210         10  FILLER                   PIC X(4).                   00000120
+000220         10  PIC X.                                               00000121
+000230         10  PIC 9(2) OCCURS 3.                                   00000122
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 19,
+				Children: []*Record{{
+					Name:   "DUMMY-GROUP-1",
+					Typ:    reflect.Struct,
+					Length: 19,
+					Children: []*Record{{
+						Name:   "DUMMY-GROUP-1-OBJECT-A",
+						Typ:    reflect.String,
+						Length: 3,
+					}, {
+						Name:   "FILLER",
+						Typ:    reflect.String,
+						Length: 2,
+						Filler: true,
+					}, {
+						Name:   "DUMMY-GROUP-1-OBJECT-B",
+						Typ:    reflect.String,
+						Length: 3,
+					}, {
+						Name:   "FILLER",
+						Typ:    reflect.String,
+						Length: 4,
+						Filler: true,
+					}, {
+						Typ:    reflect.String,
+						Length: 1,
+						Filler: true,
+					}, {
+						Typ:    reflect.Uint,
+						Length: 2,
+						Occurs: 3,
+						Filler: true,
+					}},
+				}},
+			},
 		}, {
 			name: "ValidateGrouping",
 			in: NewTree(
 				New("test",
 					`000160     05  DUMMY-GROUP-1.                                           00000115
-000170         10  DUMMY-SUB-GROUP-1.                                00000116
-000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9(9).9(2).       00000117
-000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.               00000118
-000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9(4).9(4).       00000119
-000210         10  DUMMY-OBJECT-A               PIC X.               00000120
+000170         10  DUMMY-SUB-GROUP-1.                                   00000116
+000180             15  DUMMY-GROUP-1-OBJECT-A   PIC 9(9).9(2).          00000117
+000190             15  DUMMY-GROUP-1-OBJECT-B   PIC X.                  00000118
+000200             15  DUMMY-GROUP-1-OBJECT-C   PIC 9(4).9(4).          00000119
+000210         10  DUMMY-OBJECT-A               PIC X.                  00000120
 `)),
 			want: &Record{
 				Name:   "test",
@@ -460,6 +636,7 @@ func Test_Parse(t *testing.T) {
 									Name:   "DUMMY-GROUP-1-OBJECT-A",
 									Typ:    reflect.Float64,
 									Length: 12,
+									Edited: true,
 								}, {
 									Name:   "DUMMY-GROUP-1-OBJECT-B",
 									Typ:    reflect.String,
@@ -468,6 +645,7 @@ func Test_Parse(t *testing.T) {
 									Name:   "DUMMY-GROUP-1-OBJECT-C",
 									Typ:    reflect.Float64,
 									Length: 9,
+									Edited: true,
 								},
 								},
 							}, {
@@ -479,6 +657,453 @@ func Test_Parse(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			name: "ReferenceFormatAreas",
+			in: NewTree(
+				New("test",
+					`           01  ACCOUNT.
+AB0010     05  ACCOUNT-ID               PIC 9(6).
+      * THE NAME OF THE ACCOUNT HOLDER, PIC X(9) IS IGNORED
+           05  ACCOUNT-NAME             PIC X(20).                      ACCTCOPY
+AB0030D    05  ACCOUNT-DEBUG            PIC X(3).
+      / 
+           05  ACCOUNT-TYPE             PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 27,
+				Children: []*Record{
+					{
+						Name:   "ACCOUNT-ID",
+						Typ:    reflect.Uint,
+						Length: 6,
+					}, {
+						Name:   "ACCOUNT-NAME",
+						Typ:    reflect.String,
+						Length: 20,
+					}, {
+						Name:   "ACCOUNT-TYPE",
+						Typ:    reflect.String,
+						Length: 1,
+					},
+				},
+			},
+		}, {
+			name: "IndicatorArea",
+			in: NewTree(
+				New("test",
+					`      * THE CUSTOMER'S ACCOUNT
+      *************************
+           05  ACCOUNT.
+      * UNIQUE WITHIN A BRANCH
+               10  ACCOUNT-ID           PIC 9(6).
+      D        10  ACCOUNT-DEBUG        PIC X(3).
+               10  ACCOUNT-STATUS       PIC X.
+                   88  ACCOUNT-NOTE VALUE 'AN ACCOUNT ''NOTE'' THAT RUNS
+      -            ' ON'.
+                   88  ACCOUNT-SHUT     VALUE "S".
+               10  ACCOUNT-BAL
+      -            ANCE                 PIC 9(9).
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 16,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 16,
+					Children: []*Record{
+						{
+							Name:   "ACCOUNT-ID",
+							Typ:    reflect.Uint,
+							Length: 6,
+						}, {
+							Name:   "ACCOUNT-STATUS",
+							Typ:    reflect.String,
+							Length: 1,
+							Values: []string{"AN ACCOUNT 'NOTE' THAT RUNS ON", "S"},
+						}, {
+							Name:   "ACCOUNT-BALANCE",
+							Typ:    reflect.Uint,
+							Length: 9,
+						},
+					},
+				}},
+			},
+		}, {
+			name: "IndicatorAreaWithCommentsAndDebugLines",
+			in: NewTree(
+				New("test",
+					`      * THE CUSTOMER'S ACCOUNT
+      *************************
+           05  ACCOUNT.
+      * UNIQUE WITHIN A BRANCH
+               10  ACCOUNT-ID           PIC 9(6).
+      D        10  ACCOUNT-DEBUG        PIC X(3).
+               10  ACCOUNT-STATUS       PIC X.
+                   88  ACCOUNT-NOTE VALUE 'AN ACCOUNT ''NOTE'' THAT RUNS
+      -            ' ON'.
+                   88  ACCOUNT-SHUT     VALUE "S".
+               10  ACCOUNT-BAL
+      -            ANCE                 PIC 9(9).
+`, WithComments(), WithDebugLines())),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 19,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 19,
+					Doc:    "THE CUSTOMER'S ACCOUNT",
+					Children: []*Record{
+						{
+							Name:   "ACCOUNT-ID",
+							Typ:    reflect.Uint,
+							Length: 6,
+							Doc:    "UNIQUE WITHIN A BRANCH",
+						}, {
+							Name:   "ACCOUNT-DEBUG",
+							Typ:    reflect.String,
+							Length: 3,
+						}, {
+							Name:   "ACCOUNT-STATUS",
+							Typ:    reflect.String,
+							Length: 1,
+							Values: []string{"AN ACCOUNT 'NOTE' THAT RUNS ON", "S"},
+						}, {
+							Name:   "ACCOUNT-BALANCE",
+							Typ:    reflect.Uint,
+							Length: 9,
+						},
+					},
+				}},
+			},
+		}, {
+			name: "FreeFormat",
+			in: NewTree(
+				New("test",
+					`*> THE CUSTOMER'S ACCOUNT
+05 ACCOUNT.
+  *> UNIQUE WITHIN A BRANCH
+  10 ACCOUNT-ID PIC 9(6).
+  10 ACCOUNT-STATUS PIC X. *> OPEN OR SHUT
+    88 ACCOUNT-NOTE VALUE 'AN ACCOUNT ''NOTE'' THAT RUNS ON'.
+    88 ACCOUNT-SHUT VALUE "S".
+  10 ACCOUNT-BALANCE                                                    PIC 9(9).
+`, WithComments())),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 16,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 16,
+					Doc:    "THE CUSTOMER'S ACCOUNT",
+					Children: []*Record{
+						{
+							Name:   "ACCOUNT-ID",
+							Typ:    reflect.Uint,
+							Length: 6,
+							Doc:    "UNIQUE WITHIN A BRANCH",
+						}, {
+							Name:   "ACCOUNT-STATUS",
+							Typ:    reflect.String,
+							Length: 1,
+							Values: []string{"AN ACCOUNT 'NOTE' THAT RUNS ON", "S"},
+						}, {
+							Name:   "ACCOUNT-BALANCE",
+							Typ:    reflect.Uint,
+							Length: 9,
+						},
+					},
+				}},
+			},
+		}, {
+			name: "SourceFormatDirectives",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       >>SOURCE FORMAT IS FREE
+  10 ACCOUNT-STATUS PIC X.
+>>SOURCE FORMAT IS FIXED
+               10  ACCOUNT-BALANCE      PIC 9(9).
+`, WithFormat(FixedFormat))),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 16,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 16,
+					Children: []*Record{
+						{
+							Name:   "ACCOUNT-ID",
+							Typ:    reflect.Uint,
+							Length: 6,
+						}, {
+							Name:   "ACCOUNT-STATUS",
+							Typ:    reflect.String,
+							Length: 1,
+						}, {
+							Name:   "ACCOUNT-BALANCE",
+							Typ:    reflect.Uint,
+							Length: 9,
+						},
+					},
+				}},
+			},
+		}, {
+			name: "EjectStatement",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       EJECT
+               10  ACCOUNT-STATUS       PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 7,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 7,
+					Children: []*Record{
+						{Name: "ACCOUNT-ID", Typ: reflect.Uint, Length: 6},
+						{Name: "ACCOUNT-STATUS", Typ: reflect.String, Length: 1},
+					},
+				}},
+			},
+		}, {
+			name: "Skip1Statement",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       SKIP1.
+               10  ACCOUNT-STATUS       PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 7,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 7,
+					Children: []*Record{
+						{Name: "ACCOUNT-ID", Typ: reflect.Uint, Length: 6},
+						{Name: "ACCOUNT-STATUS", Typ: reflect.String, Length: 1},
+					},
+				}},
+			},
+		}, {
+			name: "Skip2Statement",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       skip2
+               10  ACCOUNT-STATUS       PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 7,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 7,
+					Children: []*Record{
+						{Name: "ACCOUNT-ID", Typ: reflect.Uint, Length: 6},
+						{Name: "ACCOUNT-STATUS", Typ: reflect.String, Length: 1},
+					},
+				}},
+			},
+		}, {
+			name: "Skip3Statement",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       SKIP3.
+               10  ACCOUNT-STATUS       PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 7,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 7,
+					Children: []*Record{
+						{Name: "ACCOUNT-ID", Typ: reflect.Uint, Length: 6},
+						{Name: "ACCOUNT-STATUS", Typ: reflect.String, Length: 1},
+					},
+				}},
+			},
+		}, {
+			name: "TitleStatement",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID           PIC 9(6).
+       TITLE 'ACCOUNT RECORD'.
+               10  ACCOUNT-STATUS       PIC X.
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 7,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 7,
+					Children: []*Record{
+						{Name: "ACCOUNT-ID", Typ: reflect.Uint, Length: 6},
+						{Name: "ACCOUNT-STATUS", Typ: reflect.String, Length: 1},
+					},
+				}},
+			},
+		}, {
+			name: "EntriesOverManyLines",
+			in: NewTree(
+				New("test",
+					`           05  ACCOUNT.
+               10  ACCOUNT-ID OCCURS 2 TIMES
+                       PIC IS 9(6) VALUE ZERO.
+               10  ACCOUNT-BALANCE
+                       USAGE IS DISPLAY
+                       PIC S9(7)V99
+                       SIGN IS LEADING SEPARATE.
+               10  ACCOUNT-HISTORY OCCURS 3.
+                   15  ACCOUNT-MONTH PIC 99.  15  ACCOUNT-YEAR PIC 9(4).
+           77  ACCOUNT-COUNT PIC 9(3).
+`)),
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 43,
+				Children: []*Record{{
+					Name:   "ACCOUNT",
+					Typ:    reflect.Struct,
+					Length: 40,
+					Children: []*Record{
+						{
+							Name:   "ACCOUNT-ID",
+							Typ:    reflect.Uint,
+							Length: 6,
+							Occurs: 2,
+						}, {
+							Name:   "ACCOUNT-BALANCE",
+							Typ:    reflect.Float64,
+							Length: 10,
+							Edited: true,
+						}, {
+							Name:   "ACCOUNT-HISTORY",
+							Typ:    reflect.Struct,
+							Length: 6,
+							Occurs: 3,
+							Children: []*Record{
+								{
+									Name:   "ACCOUNT-MONTH",
+									Typ:    reflect.Uint,
+									Length: 2,
+								}, {
+									Name:   "ACCOUNT-YEAR",
+									Typ:    reflect.Uint,
+									Length: 4,
+								},
+							},
+						},
+					},
+				}, {
+					Name:   "ACCOUNT-COUNT",
+					Typ:    reflect.Uint,
+					Length: 3,
+				}},
+			},
+		}, {
+			name: "Usages",
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 38,
+				Children: []*Record{
+					{Name: "ACCOUNT-ID", Typ: reflect.Uint, Usage: BinaryUsage, Length: 4},
+					{Name: "BALANCE", Typ: reflect.Float64, Usage: PackedDecimalUsage, Length: 5},
+					{Name: "COUNTERS", Typ: reflect.Struct, Usage: BinaryUsage, Length: 10, Children: []*Record{
+						{Name: "SMALL-COUNT", Typ: reflect.Uint, Usage: BinaryUsage, Length: 2},
+						{Name: "BIG-COUNT", Typ: reflect.Int, Usage: BinaryUsage, Length: 8},
+					}},
+					{Name: "RATE", Typ: reflect.Float64, Usage: DoubleUsage, Length: 8},
+					{Name: "ENTRY-IDX", Typ: reflect.Int, Usage: IndexUsage, Length: 4},
+					{Name: "NATIVE-COUNT", Typ: reflect.Int, Usage: NativeBinaryUsage, Length: 4},
+					{Name: "LABEL-TEXT", Typ: reflect.String, Length: 3},
+				},
+			},
+			in: NewTree(
+				New("test",
+					`       01  ACCOUNT-REC.
+           05  ACCOUNT-ID        PIC 9(9) COMP.
+           05  BALANCE           PIC S9(7)V99 USAGE IS COMP-3.
+           05  COUNTERS          USAGE BINARY.
+               10  SMALL-COUNT   PIC 9(4).
+               10  BIG-COUNT     PIC S9(18) BINARY.
+           05  RATE              COMP-2.
+           05  ENTRY-IDX         INDEX.
+           05  NATIVE-COUNT      PIC S9(9) COMP-5.
+           05  LABEL-TEXT        PIC X(3) DISPLAY.
+`)),
+		}, {
+			name: "ImpliedUsages",
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Length: 53,
+				Children: []*Record{
+					{Name: "DBCS-NAME", Typ: reflect.String, Usage: DBCSUsage, Length: 20},
+					{Name: "NATIONAL-NAME", Typ: reflect.String, Usage: NationalUsage, Length: 20},
+					{Name: "NATIONAL-SPACED", Typ: reflect.String, Usage: NationalUsage, Length: 10},
+					{Name: "EXPLICIT-NATIONAL", Typ: reflect.String, Usage: NationalUsage, Length: 2},
+					{Name: "PLAIN-TEXT", Typ: reflect.String, Length: 1},
+				},
+			},
+			in: NewTree(
+				New("test",
+					`       01  NAMES-REC.
+           05  DBCS-NAME         PIC G(10).
+           05  NATIONAL-NAME     PIC N(10).
+           05  NATIONAL-SPACED   PIC NNBNN.
+           05  EXPLICIT-NATIONAL PIC N USAGE NATIONAL.
+           05  PLAIN-TEXT        PIC X.
+`)),
+		}, {
+			name: "RecordUsage",
+			want: &Record{
+				Name:   "test",
+				Typ:    reflect.Struct,
+				Usage:  PackedDecimalUsage,
+				Length: 5,
+				Children: []*Record{
+					{Name: "AMOUNT", Typ: reflect.Uint, Usage: PackedDecimalUsage, Length: 3},
+					{Name: "RATE", Typ: reflect.Int, Usage: PackedDecimalUsage, Length: 2},
+				},
+			},
+			in: NewTree(
+				New("test",
+					`       01  PACKED-REC COMP-3.
+           05  AMOUNT            PIC 9(5).
+           05  RATE              PIC S9(2).
+`)),
 		},
 	}
 
@@ -486,17 +1111,172 @@ func Test_Parse(t *testing.T) {
 		tt := test
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := tt.in.Parse()
+			got, err := tt.in.Parse()
+			require.NoError(t, err)
 			deepCompare(t, tt.want, got)
 		})
 	}
 }
 
+func Test_Parse_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		input string
+		want  *Error
+	}{
+		{
+			name: "MissingRedefinitionTarget",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  OTHER-OBJECT  REDEFINES  MISSING-OBJECT  PIC X(11).  00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 45,
+				Text:   "001900         10  OTHER-OBJECT  REDEFINES  MISSING-OBJECT  PIC X(11).  00000376",
+			},
+		}, {
+			name: "MissingMultilineRedefinitionTarget",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  OTHER-OBJECT  REDEFINES                              00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 34,
+				Text:   "001900         10  OTHER-OBJECT  REDEFINES                              00000376",
+			},
+		}, {
+			name: "UnknownClause",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  OTHER-OBJECT  PIC X(11) HUE RED.                     00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 44,
+				Text:   "001900         10  OTHER-OBJECT  PIC X(11) HUE RED.                     00000376",
+			},
+		}, {
+			name: "MissingPeriod",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11)                       00000375
+001900         10  OTHER-OBJECT  PIC X(11).                             00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 16,
+				Text:   "001900         10  OTHER-OBJECT  PIC X(11).                             00000376",
+			},
+		}, {
+			name: "UnterminatedEntry",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  OTHER-OBJECT  PIC X(11)                              00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 38,
+				Text:   "001900         10  OTHER-OBJECT  PIC X(11)                              00000376",
+			},
+		}, {
+			name: "SubordinateToElementaryItem",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900             15  OTHER-OBJECT  PIC X(11).                         00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 20,
+				Text:   "001900             15  OTHER-OBJECT  PIC X(11).                         00000376",
+			},
+		}, {
+			name: "BadOccursCount",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  LIST-OBJECT  PIC X(11) OCCURS 1#2.                   00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 51,
+				Text:   "001900         10  LIST-OBJECT  PIC X(11) OCCURS 1#2.                   00000376",
+			},
+		}, {
+			name: "InvalidPicture",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  OTHER-OBJECT  PIC 99ZZ.                              00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 38,
+				Text:   "001900         10  OTHER-OBJECT  PIC 99ZZ.                              00000376",
+			},
+		}, {
+			name: "UsageConflict",
+			input: `001890         10  PACKED-GROUP  COMP-3.                                00000375
+001900             15  AMOUNT-OBJECT  PIC 9(5) BINARY.                  00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 48,
+				Text:   "001900             15  AMOUNT-OBJECT  PIC 9(5) BINARY.                  00000376",
+			},
+		}, {
+			name: "PictureOfFloat",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  FLOAT-OBJECT  PIC 9(5) COMP-1.                       00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 38,
+				Text:   "001900         10  FLOAT-OBJECT  PIC 9(5) COMP-1.                       00000376",
+			},
+		}, {
+			name: "BinaryAlphanumeric",
+			input: `001890         10  REGULAR-OBJECT       PIC 9(11).                      00000375
+001900         10  BINARY-OBJECT  PIC X(4) COMP.                        00000376
+`,
+			want: &Error{
+				Name:   "test",
+				Line:   2,
+				Column: 39,
+				Text:   "001900         10  BINARY-OBJECT  PIC X(4) COMP.                        00000376",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NewTree(New("test", tt.input)).Parse()
+			require.Nil(t, got)
+
+			var e *Error
+			require.True(t, errors.As(err, &e), "want an *Error, got %v", err)
+			require.Equal(t, tt.want.Name, e.Name)
+			require.Equal(t, tt.want.Line, e.Line)
+			require.Equal(t, tt.want.Column, e.Column)
+			require.Equal(t, tt.want.Text, e.Text)
+			require.Error(t, e.Err)
+		})
+	}
+}
+
 func deepCompare(t *testing.T, want, got *Record) {
 	require.Equal(t, want.Name, got.Name, fmt.Sprintf("name mismatch: %s", want.Name))
 	require.Equal(t, want.Length, got.Length, fmt.Sprintf("length mismatch: %s", want.Name))
 	require.Equal(t, want.Typ, got.Typ, fmt.Sprintf("type mismatch: %s", want.Name))
+	require.Equal(t, want.Usage, got.Usage, fmt.Sprintf("usage mismatch: %s", want.Name))
 	require.Equal(t, want.Occurs, got.Occurs, fmt.Sprintf("occurrence mismatch: %s", want.Name))
+	require.Equal(t, want.Values, got.Values, fmt.Sprintf("values mismatch: %s", want.Name))
+	require.Equal(t, want.Filler, got.Filler, fmt.Sprintf("filler mismatch: %s", want.Name))
+	require.Equal(t, want.Doc, got.Doc, fmt.Sprintf("doc mismatch: %s", want.Name))
+	require.Equal(t, want.Edited, got.Edited, fmt.Sprintf("edited mismatch: %s", want.Name))
 	require.Equal(t, len(want.Children), len(got.Children), "nodes' children not equal, comparison not holistic")
 	if want.Typ == reflect.Struct {
 		for i, nn := range want.Children {
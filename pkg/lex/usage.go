@@ -0,0 +1,149 @@
+package lex
+
+import (
+	"reflect"
+)
+
+// Usage is the format that the data of an item is stored in, as given by the
+// USAGE clause of the item, or of the group it's in.
+type Usage int
+
+const (
+	// DisplayUsage stores each character of an item as a byte of text. It's
+	// the usage of items that have no USAGE clause.
+	DisplayUsage Usage = iota
+
+	// NationalUsage (NATIONAL) stores each character in UTF-16, as two bytes.
+	NationalUsage
+
+	// DBCSUsage (DISPLAY-1) stores each character of a double-byte character
+	// set as two bytes.
+	DBCSUsage
+
+	// BinaryUsage (BINARY, COMP, COMP-4, COMPUTATIONAL, COMPUTATIONAL-4)
+	// stores a number as a big-endian binary integer of 2, 4 or 8 bytes, for
+	// up to 4, 9 or 18 digits.
+	BinaryUsage
+
+	// NativeBinaryUsage (COMP-5, COMPUTATIONAL-5) stores a number as
+	// BinaryUsage does, but its value may take all the bits of its storage,
+	// rather than being limited to the digits of its PIC clause.
+	NativeBinaryUsage
+
+	// PackedDecimalUsage (PACKED-DECIMAL, COMP-3, COMPUTATIONAL-3) stores two
+	// digits of a number in each byte, with its sign in the last half byte.
+	PackedDecimalUsage
+
+	// FloatUsage (COMP-1, COMPUTATIONAL-1) stores a number as a 4 byte
+	// floating point number. It takes no PIC clause.
+	FloatUsage
+
+	// DoubleUsage (COMP-2, COMPUTATIONAL-2) stores a number as an 8 byte
+	// floating point number. It takes no PIC clause.
+	DoubleUsage
+
+	// IndexUsage (INDEX) stores the 4 byte index of a table entry. It takes no
+	// PIC clause.
+	IndexUsage
+
+	// PointerUsage (POINTER) stores a 4 byte data address. It takes no PIC
+	// clause.
+	PointerUsage
+
+	// ProcedurePointerUsage (PROCEDURE-POINTER) stores the 8 byte address of
+	// a program entry point. It takes no PIC clause.
+	ProcedurePointerUsage
+
+	// FunctionPointerUsage (FUNCTION-POINTER) stores the 4 byte address of a
+	// function entry point. It takes no PIC clause.
+	FunctionPointerUsage
+)
+
+var (
+	// usages are the words that may make up a USAGE clause, and the usage
+	// that each gives
+	usages = map[string]Usage{
+		"DISPLAY": DisplayUsage, "NATIONAL": NationalUsage, "DISPLAY-1": DBCSUsage,
+		"BINARY": BinaryUsage, "COMP": BinaryUsage, "COMP-4": BinaryUsage,
+		"COMPUTATIONAL": BinaryUsage, "COMPUTATIONAL-4": BinaryUsage,
+		"COMP-5": NativeBinaryUsage, "COMPUTATIONAL-5": NativeBinaryUsage,
+		"PACKED-DECIMAL": PackedDecimalUsage, "COMP-3": PackedDecimalUsage,
+		"COMPUTATIONAL-3": PackedDecimalUsage, "COMP-1": FloatUsage,
+		"COMPUTATIONAL-1": FloatUsage, "COMP-2": DoubleUsage, "COMPUTATIONAL-2": DoubleUsage,
+		"INDEX": IndexUsage, "POINTER": PointerUsage,
+		"PROCEDURE-POINTER": ProcedurePointerUsage, "FUNCTION-POINTER": FunctionPointerUsage,
+	}
+
+	// impliedUsages are the usages implied by the category of a PIC clause,
+	// for items that would otherwise be DISPLAY: N pictures are NATIONAL, and
+	// G pictures DISPLAY-1
+	impliedUsages = map[Category]Usage{
+		NationalCategory: NationalUsage, NationalEditedCategory: NationalUsage, DBCSCategory: DBCSUsage,
+	}
+
+	usageNames = map[Usage]string{
+		DisplayUsage: "DISPLAY", NationalUsage: "NATIONAL", DBCSUsage: "DISPLAY-1",
+		BinaryUsage: "BINARY", NativeBinaryUsage: "COMP-5", PackedDecimalUsage: "PACKED-DECIMAL",
+		FloatUsage: "COMP-1", DoubleUsage: "COMP-2", IndexUsage: "INDEX", PointerUsage: "POINTER",
+		ProcedurePointerUsage: "PROCEDURE-POINTER", FunctionPointerUsage: "FUNCTION-POINTER",
+	}
+)
+
+// String returns the word of a USAGE clause that gives the usage.
+func (u Usage) String() string {
+	return usageNames[u]
+}
+
+// hasPicture reports whether items of the usage are described by a PIC
+// clause.
+func (u Usage) hasPicture() bool {
+	return u <= PackedDecimalUsage
+}
+
+// numeric reports whether items of the usage can only hold numbers, so must
+// have a numeric PIC clause, if any.
+func (u Usage) numeric() bool {
+	return u >= BinaryUsage && u <= DoubleUsage
+}
+
+// kind returns the Go type of items of a usage that takes no PIC clause.
+func (u Usage) kind() reflect.Kind {
+	switch u {
+	case FloatUsage, DoubleUsage:
+		return reflect.Float64
+	case IndexUsage:
+		return reflect.Int
+	}
+
+	return reflect.Uint
+}
+
+// size returns the number of bytes that an item of the usage takes, given the
+// number of character positions, and of digit positions, of its PIC clause.
+func (u Usage) size(positions, digits int) int {
+	switch u {
+	case NationalUsage, DBCSUsage:
+		return 2 * positions // nolint:gomnd // two bytes per character
+
+	case BinaryUsage, NativeBinaryUsage:
+		switch {
+		case digits <= 4: // nolint:gomnd // halfword
+			return 2
+		case digits <= 9: // nolint:gomnd // fullword
+			return 4
+		}
+
+		return 8
+
+	case PackedDecimalUsage:
+		return digits/2 + 1
+
+	case FloatUsage, IndexUsage, PointerUsage, FunctionPointerUsage:
+		return 4
+
+	case DoubleUsage, ProcedurePointerUsage:
+		return 8
+	}
+
+	return positions
+}
@@ -0,0 +1,153 @@
+package pic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// RecordOption configures a RecordReader. Every Option is a RecordOption,
+// configuring how records are decoded, while WithHeader and WithSeparator
+// describe how records are laid out in the input, so only configure a
+// RecordReader.
+type RecordOption interface {
+	applyRecord(*recordConfig)
+}
+
+// recordConfig holds the options of a RecordReader.
+type recordConfig struct {
+	opts      []Option
+	header    int64
+	separator string
+}
+
+func (o Option) applyRecord(c *recordConfig) {
+	c.opts = append(c.opts, o)
+}
+
+// layoutOption is a RecordOption that only applies to a RecordReader.
+type layoutOption func(*recordConfig)
+
+func (o layoutOption) applyRecord(c *recordConfig) {
+	o(c)
+}
+
+// WithHeader skips the first n bytes of the input of a RecordReader, such as a
+// header record, before the first record.
+func WithHeader(n int64) RecordOption {
+	return layoutOption(func(c *recordConfig) {
+		c.header = n
+	})
+}
+
+// WithSeparator sets the separator that follows each record in the input of a
+// RecordReader, e.g. "\n" or "\r\n". By default records follow each other
+// directly.
+func WithSeparator(sep string) RecordOption {
+	return layoutOption(func(c *recordConfig) {
+		c.separator = sep
+	})
+}
+
+// RecordReader reads fixed-length records of a single struct type at random
+// from an io.ReaderAt, without scanning the records before them. The length of
+// each record is that of the struct type, and records may be preceded by a
+// header (WithHeader) and followed by a separator (WithSeparator).
+//
+// Like a Codec, a RecordReader is not safe for concurrent use.
+type RecordReader struct {
+	r      io.ReaderAt
+	c      *Codec
+	header int64
+	stride int64 // length of a record and its separator
+	buf    []byte
+}
+
+// NewRecordReader builds a RecordReader for records of the struct type t, or a
+// pointer to it, read from r.
+func NewRecordReader(r io.ReaderAt, t reflect.Type, opts ...RecordOption) (*RecordReader, error) {
+	var cfg recordConfig
+	for _, opt := range opts {
+		opt.applyRecord(&cfg)
+	}
+
+	if cfg.header < 0 {
+		return nil, fmt.Errorf("pic: negative header length %d", cfg.header)
+	}
+
+	c, err := Compile(t, cfg.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	length := cachedStructRepresentation(c.typ).len
+	if length == 0 {
+		return nil, fmt.Errorf("pic: cannot read records of zero length type %s", c.typ)
+	}
+
+	return &RecordReader{
+		r:      r,
+		c:      c,
+		header: cfg.header,
+		stride: int64(length + len(cfg.separator)),
+		buf:    make([]byte, length),
+	}, nil
+}
+
+// RecordAt decodes the n-th record, counting from 0, into v, which must be a
+// non-nil pointer to the reader's type. It returns io.EOF if the input ends
+// before the record, and decodes a record cut short by the end of the input
+// like Unmarshal decodes a short line.
+func (rr *RecordReader) RecordAt(n int64, v interface{}) error {
+	if n < 0 {
+		return fmt.Errorf("pic: negative record number %d", n)
+	}
+
+	read, err := rr.r.ReadAt(rr.buf, rr.header+n*rr.stride)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if read == 0 {
+		return io.EOF
+	}
+
+	return rr.c.DecodeBytes(rr.buf[:read], v)
+}
+
+// Count returns the number of records in the input, which must have a size,
+// like an *os.File, or anything with a Size method such as *bytes.Reader or
+// *io.SectionReader. A last record that's cut short, or isn't followed by a
+// separator, is counted.
+func (rr *RecordReader) Count() (int64, error) {
+	size, err := sizeOf(rr.r)
+	if err != nil {
+		return 0, err
+	}
+
+	size -= rr.header
+	if size <= 0 {
+		return 0, nil
+	}
+
+	return (size + rr.stride - 1) / rr.stride, nil
+}
+
+func sizeOf(r io.ReaderAt) (int64, error) {
+	switch s := r.(type) {
+	case interface{ Size() int64 }:
+		return s.Size(), nil
+
+	case interface{ Stat() (os.FileInfo, error) }:
+		fi, err := s.Stat()
+		if err != nil {
+			return 0, err
+		}
+
+		return fi.Size(), nil
+	}
+
+	return 0, fmt.Errorf("pic: cannot count records of %T, as its size is unknown", r)
+}
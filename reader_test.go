@@ -0,0 +1,120 @@
+package pic
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReader(t *testing.T) {
+	type record struct {
+		ID   string `pic:"3"`
+		Qty  int    `pic:"4"`
+		Flag string `pic:"1"`
+	}
+
+	for _, test := range []struct {
+		name  string
+		data  string
+		opts  []RecordOption
+		count int64
+	}{
+		{
+			name:  "Contiguous",
+			data:  "AAA0001YBBB0002NCCC0003Y",
+			count: 3,
+		}, {
+			name:  "HeaderAndSeparator",
+			data:  "HEADER\nAAA0001Y\nBBB0002N\nCCC0003Y\n",
+			opts:  []RecordOption{WithHeader(7), WithSeparator("\n")},
+			count: 3,
+		}, {
+			name:  "NoTrailingSeparator",
+			data:  "AAA0001Y\r\nBBB0002N\r\nCCC0003Y",
+			opts:  []RecordOption{WithSeparator("\r\n")},
+			count: 3,
+		}, {
+			name:  "DecoderOptions",
+			data:  "HEADER\nAAA0001Y\nBBB0002N\nCCC0003Y\n",
+			opts:  []RecordOption{WithValidation(), WithHeader(7), WithNullPolicy(NullSpaces), WithSeparator("\n")},
+			count: 3,
+		},
+	} {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := NewRecordReader(strings.NewReader(tt.data), reflect.TypeOf(record{}), tt.opts...)
+			require.NoError(t, err)
+
+			n, err := rr.Count()
+			require.NoError(t, err)
+			require.Equal(t, tt.count, n)
+
+			got := record{}
+			require.NoError(t, rr.RecordAt(2, &got))
+			require.Equal(t, record{"CCC", 3, "Y"}, got)
+
+			require.NoError(t, rr.RecordAt(0, &got))
+			require.Equal(t, record{"AAA", 1, "Y"}, got)
+
+			require.Equal(t, io.EOF, rr.RecordAt(3, &got))
+			require.IsType(t, &InvalidUnmarshalError{}, rr.RecordAt(0, nil))
+		})
+	}
+
+	t.Run("ShortRecord", func(t *testing.T) {
+		rr, err := NewRecordReader(bytes.NewReader([]byte("AAA0001YBBB02")), reflect.TypeOf(&record{}))
+		require.NoError(t, err)
+
+		got := record{}
+		require.NoError(t, rr.RecordAt(1, &got))
+		require.Equal(t, record{ID: "BBB", Qty: 2}, got)
+
+		n, err := rr.Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "records")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		_, err = f.WriteString("AAA0001YBBB0002N")
+		require.NoError(t, err)
+
+		rr, err := NewRecordReader(f, reflect.TypeOf(record{}))
+		require.NoError(t, err)
+
+		n, err := rr.Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := NewRecordReader(strings.NewReader(""), reflect.TypeOf(record{}), WithHeader(-1))
+		require.EqualError(t, err, "pic: negative header length -1")
+
+		_, err = NewRecordReader(strings.NewReader(""), reflect.TypeOf(struct{}{}))
+		require.EqualError(t, err, "pic: cannot read records of zero length type struct {}")
+
+		rr, err := NewRecordReader(unsized{}, reflect.TypeOf(record{}))
+		require.NoError(t, err)
+		require.EqualError(t, rr.RecordAt(-1, &record{}), "pic: negative record number -1")
+
+		_, err = rr.Count()
+		require.EqualError(t, err, "pic: cannot count records of pic.unsized, as its size is unknown")
+	})
+}
+
+type unsized struct{}
+
+func (unsized) ReadAt([]byte, int64) (int, error) {
+	return 0, io.EOF
+}
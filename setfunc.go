@@ -1,30 +1,54 @@
 package pic
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
-type setFunc func(v reflect.Value, s string) error
+type setFunc func(d *decoder, v reflect.Value, s string) error
+
+const (
+	// insertionSymbols are numeric-edited symbols that carry no value, B
+	// insertion and Z suppression both appear as spaces in data
+	insertionSymbols = " $*,/"
+	creditSymbol     = "CR"
+	debitSymbol      = "DB"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+func newSetFunc(t reflect.Type, picSize, occursSize int, edited bool) setFunc {
+	return nullSetFunc(kindSetFunc(t, picSize, occursSize, edited))
+}
+
+// kindSetFunc returns the setter for a field of type t. Numbers of a field
+// tagged edited are unedited before they're parsed, other numbers must be
+// plain.
+func kindSetFunc(t reflect.Type, picSize, occursSize int, edited bool) setFunc {
+	// sql.Null* types, and other Optional-style wrappers, set themselves
+	if reflect.PtrTo(t).Implements(scannerType) {
+		return scannerSetFunc
+	}
 
-func newSetFunc(t reflect.Type, picSize, occursSize int) setFunc {
 	switch t.Kind() {
 	case reflect.String:
 		return strSetFunc
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return intSetFunc
+		return editable(intSetFunc, edited)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return uintSetFunc
+		return editable(uintSetFunc, edited)
 	case reflect.Float32:
-		return floatSetFunc(32) // nolint:gomnd
+		return editable(floatSetFunc(32), edited) // nolint:gomnd
 	case reflect.Float64:
-		return floatSetFunc(64) // nolint:gomnd
+		return editable(floatSetFunc(64), edited) // nolint:gomnd
 	case reflect.Slice:
-		return arraySetFunc(picSize, occursSize)
+		return arraySetFunc(t, picSize, occursSize, edited)
 	case reflect.Ptr:
-		return ptrSetFunc(t)
+		return ptrSetFunc(t, picSize, occursSize, edited)
 	case reflect.Interface:
 		return ifaceSetFunc
 	case reflect.Struct:
@@ -33,12 +57,12 @@ func newSetFunc(t reflect.Type, picSize, occursSize int) setFunc {
 	return failSetFunc
 }
 
-func strSetFunc(v reflect.Value, s string) error {
+func strSetFunc(_ *decoder, v reflect.Value, s string) error {
 	v.SetString(s)
 	return nil
 }
 
-func intSetFunc(v reflect.Value, s string) error {
+func intSetFunc(_ *decoder, v reflect.Value, s string) error {
 	if len(s) < 1 {
 		return nil
 	}
@@ -48,11 +72,15 @@ func intSetFunc(v reflect.Value, s string) error {
 		return fmt.Errorf("failed string->int conversion: %w", err)
 	}
 
+	if v.OverflowInt(int64(i)) {
+		return fmt.Errorf("%d overflows %s", i, v.Type())
+	}
+
 	v.SetInt(int64(i))
 	return nil
 }
 
-func uintSetFunc(v reflect.Value, s string) error {
+func uintSetFunc(_ *decoder, v reflect.Value, s string) error {
 	if len(s) < 1 {
 		return nil
 	}
@@ -62,12 +90,73 @@ func uintSetFunc(v reflect.Value, s string) error {
 		return fmt.Errorf("failed string->int conversion: %w", err)
 	}
 
+	if v.OverflowUint(i) {
+		return fmt.Errorf("%d overflows %s", i, v.Type())
+	}
+
 	v.SetUint(i)
 	return nil
 }
 
+// editable returns set, unediting the data before setting it if edited is set.
+func editable(set setFunc, edited bool) setFunc {
+	if !edited {
+		return set
+	}
+
+	return func(d *decoder, v reflect.Value, s string) error {
+		return set(d, v, unedit(s))
+	}
+}
+
+// unedit converts numeric-edited data, such as "  1,234.56CR" or
+// "$****12.00-", into a plain signed number that strconv can parse, e.g.
+// "-1234.56". Data that isn't edited is returned as is.
+func unedit(s string) string {
+	neg := false
+	switch {
+	case strings.HasSuffix(s, creditSymbol), strings.HasSuffix(s, debitSymbol):
+		neg = true
+		s = s[:len(s)-len(creditSymbol)]
+
+	case strings.HasSuffix(s, "-"):
+		neg = true
+		s = s[:len(s)-1]
+
+	case strings.HasSuffix(s, "+"):
+		s = s[:len(s)-1]
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(insertionSymbols, r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	// check protected zeroes are edited to only asterisks, e.g. ***.**
+	if s == "." {
+		return ""
+	}
+
+	if neg && s != "" {
+		return "-" + s
+	}
+
+	return s
+}
+
 func floatSetFunc(size int) setFunc {
-	return func(v reflect.Value, s string) error {
+	return func(_ *decoder, v reflect.Value, s string) error {
 		if len(s) < 1 {
 			return nil
 		}
@@ -82,11 +171,16 @@ func floatSetFunc(size int) setFunc {
 	}
 }
 
-func arraySetFunc(l, count int) setFunc {
-	return func(v reflect.Value, s string) error {
+func arraySetFunc(t reflect.Type, l, count int, edited bool) setFunc {
+	sf := newSetFunc(t.Elem(), 0, 0, edited)
+	return func(d *decoder, v reflect.Value, s string) error {
+		if count <= 0 {
+			return fmt.Errorf("%s has no occurs count", t)
+		}
+
 		size := l / count
 		if len(s) == 0 {
-			return nilSetFunc(v, s)
+			return nilSetFunc(d, v, s)
 		}
 
 		if v.IsNil() {
@@ -94,13 +188,12 @@ func arraySetFunc(l, count int) setFunc {
 		}
 
 		many := reflect.MakeSlice(v.Type(), count, count)
-		sf := newSetFunc(v.Type().Elem(), 0, 0)
 		track := 1
 
 		for i := 0; i < count; i++ {
 			next := track + size
 			val := newValFromLine(s, track, next-1)
-			if err := sf(many.Index(i), val); err != nil {
+			if err := sf(d, many.Index(i), val); err != nil {
 				return errors.New("failed to set array data" + val + " " + s)
 			}
 			track = next
@@ -112,49 +205,121 @@ func arraySetFunc(l, count int) setFunc {
 	}
 }
 
-func ptrSetFunc(t reflect.Type) setFunc {
-	innerSetter := newSetFunc(t.Elem(), 0, 0)
-	return func(v reflect.Value, s string) error {
+func ptrSetFunc(t reflect.Type, picSize, occursSize int, edited bool) setFunc {
+	innerSetter := newSetFunc(t.Elem(), picSize, occursSize, edited)
+	return func(d *decoder, v reflect.Value, s string) error {
 		if len(s) == 0 {
-			return nilSetFunc(v, s)
+			return nilSetFunc(d, v, s)
 		}
 
 		if v.IsNil() {
 			v.Set(reflect.New(t.Elem()))
 		}
 
-		return innerSetter(reflect.Indirect(v), s)
+		return innerSetter(d, reflect.Indirect(v), s)
 	}
 }
 
-func ifaceSetFunc(v reflect.Value, s string) error {
-	return newSetFunc(v.Elem().Type(), 0, 0)(v.Elem(), s)
+func scannerSetFunc(_ *decoder, v reflect.Value, s string) error {
+	sc, ok := v.Addr().Interface().(sql.Scanner)
+	if !ok {
+		return errors.New("pic: value does not implement sql.Scanner")
+	}
+
+	if err := sc.Scan(s); err != nil {
+		return fmt.Errorf("failed to scan value: %w", err)
+	}
+
+	return nil
 }
 
-func structSetFunc(t reflect.Type) setFunc {
+// ifaceSetFunc decodes into the value already held by an interface, which
+// must be a pointer for the value to be settable. Interfaces without a value
+// need a select tag and registered variants instead.
+func ifaceSetFunc(d *decoder, v reflect.Value, s string) error {
+	if v.IsNil() {
+		return fmt.Errorf("pic: cannot decode into nil %s without a select tag", v.Type())
+	}
+
+	return newSetFunc(v.Elem().Type(), 0, 0, false)(d, v.Elem(), s)
+}
+
+func structSetFunc(t reflect.Type) setFunc { // nolint:gocyclo
 	spec := cachedStructRepresentation(t)
-	return func(v reflect.Value, s string) error {
+	return func(d *decoder, v reflect.Value, s string) error {
+		prefix := d.prefix
 		for i, ff := range spec.fields {
 			if ff.err != nil {
+				sf := t.Field(i)
+				return &TagError{sf.Type, t.Name(), sf.Name, ff.err}
+			}
+
+			if ff.skip {
 				continue
 			}
 
 			val := newValFromLine(s, ff.start, ff.end)
-			err := ff.setFunc(v.Field(i), val)
+			var ts []Transform
+			if d.transforming() {
+				sf := t.Field(i)
+				path := prefix + sf.Name
+				var err error
+				ts, err = d.transformsFor(path, ff.transforms)
+				if err == nil {
+					val, err = transformRaw(ts, val)
+				}
+
+				if err != nil {
+					return &UnmarshalTypeError{s, sf.Type, t.Name(), sf.Name, err}
+				}
+
+				// fields of inline structs are keyed like promoted fields
+				if !ff.inline {
+					d.prefix = path + "."
+				}
+			}
+
+			null := d.null
+			if ff.nullOverride {
+				d.null = ff.null
+			}
+
+			// absent fields have no value to validate, as with a Codec
+			absent := d.null.isNull(val)
+
+			var err error
+			if ff.selector >= 0 {
+				sel := spec.fields[ff.selector]
+				err = d.setVariant(v.Field(i), newValFromLine(s, sel.start, sel.end), val)
+			} else {
+				err = ff.setFunc(d, v.Field(i), val)
+			}
+
+			if err == nil && len(ts) > 0 {
+				err = transformValue(ts, v.Field(i))
+			}
+
+			d.null = null
+			d.prefix = prefix
 			if err != nil {
 				sf := t.Field(i)
 				return &UnmarshalTypeError{s, sf.Type, t.Name(), sf.Name, err}
 			}
+
+			if d.validate && !absent && !ff.allows(val) {
+				sf := t.Field(i)
+				d.violations = append(d.violations, &ValidationError{val, ff.values, t.Name(), sf.Name})
+			}
 		}
 		return nil
 	}
 }
 
-func failSetFunc(_ reflect.Value, _ string) error {
+func failSetFunc(_ *decoder, _ reflect.Value, _ string) error {
 	return errors.New("pic: unknown type")
 }
 
-func nilSetFunc(v reflect.Value, _ string) error {
+func nilSetFunc(_ *decoder, v reflect.Value, _ string) error {
 	v.Set(reflect.Zero(v.Type()))
 	return nil
 }
@@ -1,6 +1,7 @@
 package pic
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -9,9 +10,17 @@ import (
 )
 
 const (
-	// when a tag is split on ',' if there are 2 elements,
-	// the second indicates the occurs count
-	occursIndicator = 2
+	// skipOption marks a field, such as a FILLER, whose bytes are
+	// accounted for in the layout but never decoded, e.g. `pic:"10,skip"`
+	skipOption = "skip"
+
+	// editedOption marks a numeric field whose data is edited for printing,
+	// e.g. `pic:"12,edited"` for PIC ZZZ,ZZ9.99CR, so that its editing
+	// symbols are removed before it's decoded
+	editedOption = "edited"
+
+	// blankField is the name of blank (_) fields, which are always skipped
+	blankField = "_"
 )
 
 var fieldRepCache sync.Map // map[reflect.Type]structRepresentation
@@ -24,33 +33,239 @@ type structRepresentation struct {
 type fieldRepresentation struct {
 	setFunc         setFunc
 	len, start, end int
+	occurs          int
+	redefines       int // index of the field redefined, or -1
+	selector        int // index of the field selecting the variant, or -1
+	skip            bool
+	edited          bool // numeric-edited field, unedited before decoding
+	inline          bool // untagged embedded struct, decoded in place
+	values          []string
+	transforms      []string
+	null            NullPolicy
+	nullOverride    bool
 	err             error
 }
 
-func parseTag(tag string, prev int) (int, int, int, int, error) {
-	var occursSize int
-	ss := strings.Split(tag, ",")
-	if len(ss) == occursIndicator {
-		o, err := strconv.Atoi(ss[1])
-		if err != nil {
-			return 0, 0, 0, 0, fmt.Errorf("failed string->int conversion: %w", err)
+// allows reports whether the raw value s is one of the field's allowed values.
+// Fields without a values tag allow anything.
+func (f fieldRepresentation) allows(s string) bool {
+	return allowed(f.values, s)
+}
+
+func allowed(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+
+		// numeric conditions such as VALUE 1 should match zero-padded data
+		a, errA := strconv.ParseFloat(s, 64)
+		b, errB := strconv.ParseFloat(v, 64)
+		if errA == nil && errB == nil && a == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseValues splits a values tag, e.g. `values:"A,C,D"`, into its allowed
+// values. A backslash escapes the character after it, so that a value may
+// hold a comma, e.g. `values:"A\\,B"`.
+func parseValues(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+
+	var vs []string
+	var b strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == '\\' && i+1 < len(tag):
+			i++
+			b.WriteByte(tag[i])
+		case c == ',':
+			vs = append(vs, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return append(vs, strings.TrimSpace(b.String()))
+}
+
+// valueEscaper escapes the backslashes and commas of a value of a values tag.
+var valueEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`)
+
+// JoinValues joins allowed values into a values tag, escaping the commas and
+// backslashes within them, so that ParseTag splits the tag back into the same
+// values.
+func JoinValues(values []string) string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = valueEscaper.Replace(v)
+	}
+
+	return strings.Join(escaped, ",")
+}
+
+// Tag holds the parsed pic tags of a struct field, e.g.
+// `pic:"13,12" offset:"40" values:"A,C" null:"spaces"`
+type Tag struct {
+	Length    int        // total length, including all occurrences
+	Occurs    int        // occurs count, 0 if not an OCCURS field
+	Skip      bool       // field occupies its length but is not decoded
+	Edited    bool       // field holds numeric-edited data
+	Offset    int        // 0-based offset within its struct, -1 if not set
+	Redefines string     // name of the field whose bytes are redefined
+	Select    string     // name of the field selecting an interface's variant
+	Transform []string   // names of the transforms of the field
+	Values    []string   // allowed values
+	Null      NullPolicy // sentinels that mark the field as absent
+	NullSet   bool       // Null overrides the decoder's null policy
+}
+
+// ParseTag parses the pic tags of a struct field. The pic tag itself is
+// required, the offset, redefines, select, values, null and transform tags are
+// optional.
+func ParseTag(st reflect.StructTag) (Tag, error) {
+	pt, ok := st.Lookup("pic")
+	if !ok {
+		return Tag{}, errors.New("missing pic tag")
+	}
+
+	t, err := parseTag(pt)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	t.Offset = -1
+	if o, ok := st.Lookup("offset"); ok {
+		if t.Offset, err = parseOffset(o); err != nil {
+			return Tag{}, err
+		}
+	}
+
+	if nt, ok := st.Lookup("null"); ok {
+		if t.Null, err = parseNullPolicy(nt); err != nil {
+			return Tag{}, err
+		}
+		t.NullSet = true
+	}
+
+	t.Redefines = st.Get("redefines")
+	t.Select = st.Get("select")
+	t.Transform = parseValues(st.Get("transform"))
+	t.Values = parseValues(st.Get("values"))
+	return t, nil
+}
+
+// parseTag parses a pic tag, e.g. `pic:"13,12"`, `pic:"10,skip"` or
+// `pic:"12,edited"`.
+func parseTag(s string) (Tag, error) {
+	var t Tag
+	ss := strings.Split(s, ",")
+	for _, opt := range ss[1:] {
+		switch opt = strings.TrimSpace(opt); opt {
+		case skipOption:
+			t.Skip = true
+
+		case editedOption:
+			t.Edited = true
+
+		default:
+			o, err := strconv.Atoi(opt)
+			if err != nil {
+				return Tag{}, fmt.Errorf("failed string->int conversion: %w", err)
+			}
+
+			if o <= 0 {
+				return Tag{}, fmt.Errorf("occurs count %d is not positive", o)
+			}
+			t.Occurs = o
 		}
-		occursSize = o
 	}
 
 	length, err := strconv.Atoi(ss[0])
 	if err != nil {
-		return 0, 0, 0, 0, fmt.Errorf("failed string->int conversion: %w", err)
+		return Tag{}, fmt.Errorf("failed string->int conversion: %w", err)
 	}
 
-	if occursSize > 0 {
-		length *= occursSize
+	if length <= 0 {
+		return Tag{}, fmt.Errorf("length %d is not positive", length)
 	}
 
-	return length, prev + 1, length + prev, occursSize, nil
+	if t.Occurs > 0 {
+		length *= t.Occurs
+	}
+
+	t.Length = length
+	return t, nil
+}
+
+// parseOffset parses an offset tag, e.g. `offset:"120"`, holding the 0-based
+// offset of a field within its struct.
+func parseOffset(s string) (int, error) {
+	o, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed string->int conversion: %w", err)
+	}
+
+	if o < 0 {
+		return 0, fmt.Errorf("negative offset %d", o)
+	}
+
+	return o, nil
 }
 
-func makeStructRepresentation(t reflect.Type) structRepresentation {
+// parseRedefines finds the field named by a redefines tag, e.g.
+// `redefines:"Amount"`, among the fields declared before field i of t.
+func parseRedefines(t reflect.Type, i int, name string) (int, error) {
+	if j := fieldBefore(t, i, name); j >= 0 {
+		return j, nil
+	}
+
+	return -1, fmt.Errorf("redefined field %s is not declared before %s", name, t.Field(i).Name)
+}
+
+// parseSelect finds the field named by the select tag of the interface field
+// i of t, e.g. `select:"Kind"`, among the fields declared before it.
+func parseSelect(t reflect.Type, i int, name string) (int, error) {
+	if t.Field(i).Type.Kind() != reflect.Interface {
+		return -1, fmt.Errorf("select tag on non-interface field %s", t.Field(i).Name)
+	}
+
+	if j := fieldBefore(t, i, name); j >= 0 {
+		return j, nil
+	}
+
+	return -1, fmt.Errorf("select field %s is not declared before %s", name, t.Field(i).Name)
+}
+
+// fieldBefore returns the index of the field called name among the fields
+// declared before field i of t, or -1.
+func fieldBefore(t reflect.Type, i int, name string) int {
+	for j := 0; j < i; j++ {
+		if t.Field(j).Name == name {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// makeStructRepresentation lays out the fields of t. A field starts at its
+// 0-based offset tag, e.g. `offset:"120"`, at the start of the field named by
+// its redefines tag, e.g. `redefines:"Amount"`, or else right after the field
+// declared before it. Untagged embedded structs are laid out in place, as if
+// their fields were declared in t. Fields may only overlap if one redefines the
+// other, or both redefine the same field.
+func makeStructRepresentation(t reflect.Type) structRepresentation { // nolint:gocyclo
 	sr := structRepresentation{
 		fields: make([]fieldRepresentation, t.NumField()),
 	}
@@ -59,14 +274,83 @@ func makeStructRepresentation(t reflect.Type) structRepresentation {
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
 
-		l, s, e, occursSize, err := parseTag(f.Tag.Get("pic"), last)
-		last = e
+		_, tagged := f.Tag.Lookup("pic")
+		inline := !tagged && f.Anonymous && isGroup(f.Type)
+		if !tagged && !inline {
+			// fields without a pic tag aren't part of the record
+			sr.fields[i].redefines = -1
+			sr.fields[i].selector = -1
+			sr.fields[i].skip = true
+			sr.fields[i].start = last + 1
+			sr.fields[i].end = last
+
+			// an embedded pointer can't be laid out in place, as it may be nil
+			if f.Anonymous && f.Type.Kind() == reflect.Ptr && isGroup(f.Type.Elem()) && hasPicTags(f.Type.Elem()) {
+				sr.fields[i].err = fmt.Errorf("embedded %s must have a pic tag, or be embedded by value to be laid out in place", f.Type)
+			}
+			continue
+		}
+
+		var tg Tag
+		var err error
+		if inline {
+			// untagged embedded structs are inline, their fields continuing the
+			// sequence of the struct embedding them
+			tg = Tag{Length: cachedStructRepresentation(f.Type).len, Offset: -1}
+		} else {
+			tg, err = ParseTag(f.Tag)
+		}
+
+		start := last
+		sr.fields[i].redefines = -1
+		if tg.Redefines != "" && err == nil {
+			if sr.fields[i].redefines, err = parseRedefines(t, i, tg.Redefines); err == nil {
+				start = sr.fields[sr.fields[i].redefines].start - 1
+			}
+		}
+
+		if tg.Offset >= 0 {
+			start = tg.Offset
+		}
+
+		sr.fields[i].selector = -1
+		if tg.Select != "" && err == nil {
+			sr.fields[i].selector, err = parseSelect(t, i, tg.Select)
+		}
+
+		sr.fields[i].len = tg.Length
+		sr.fields[i].start = start + 1
+		sr.fields[i].end = start + tg.Length
+		sr.fields[i].occurs = tg.Occurs
+		sr.fields[i].skip = tg.Skip || f.Name == blankField
+		sr.fields[i].edited = tg.Edited
+		sr.fields[i].values = tg.Values
+		sr.fields[i].transforms = tg.Transform
+		sr.fields[i].null = tg.Null
+		sr.fields[i].nullOverride = tg.NullSet
+
+		if err == nil && !sr.fields[i].skip && !inline {
+			err = checkField(f, tg)
+		}
+
+		if err == nil {
+			err = sr.overlaps(t, i)
+		}
 
-		sr.fields[i].len = l
-		sr.fields[i].start = s
-		sr.fields[i].end = e
 		sr.fields[i].err = err
-		sr.fields[i].setFunc = newSetFunc(f.Type, l, occursSize)
+		sr.fields[i].inline = inline
+		if inline {
+			// absent values are left to the inline fields themselves
+			sr.fields[i].setFunc = kindSetFunc(f.Type, tg.Length, 0, false)
+		} else {
+			sr.fields[i].setFunc = newSetFunc(f.Type, tg.Length, tg.Occurs, tg.Edited)
+		}
+
+		// a redefinition never moves the following fields back
+		if sr.fields[i].redefines < 0 || sr.fields[i].end > last {
+			last = sr.fields[i].end
+		}
+
 		if sr.fields[i].end > sr.len {
 			sr.len = sr.fields[i].end
 		}
@@ -75,6 +359,71 @@ func makeStructRepresentation(t reflect.Type) structRepresentation {
 	return sr
 }
 
+// hasPicTags reports whether the struct t has pic tagged fields, itself or in
+// the structs embedded in it by value, so is laid out as part of a record.
+func hasPicTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("pic"); ok {
+			return true
+		}
+
+		if f.Anonymous && isGroup(f.Type) && hasPicTags(f.Type) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkField checks that the tagged field f, which isn't skipped, can be
+// decoded into, given its tags tg.
+func checkField(f reflect.StructField, tg Tag) error {
+	t := f.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch k := t.Kind(); {
+	case f.PkgPath != "":
+		return errors.New("unexported field can't be decoded into")
+	case (k == reflect.Slice || k == reflect.Array) && tg.Occurs == 0:
+		return fmt.Errorf("%s field has no occurs count", k)
+	}
+
+	return nil
+}
+
+// overlaps checks that field i of t doesn't overlap any field declared before
+// it, unless it's a redefinition of that field, or of the same field.
+func (sr structRepresentation) overlaps(t reflect.Type, i int) error {
+	f := sr.fields[i]
+	for j, g := range sr.fields[:i] {
+		if f.len == 0 || g.len == 0 || g.err != nil || f.start > g.end || g.start > f.end {
+			continue
+		}
+
+		if sr.redefined(i) == sr.redefined(j) {
+			continue
+		}
+
+		return fmt.Errorf("field %s (%d-%d) overlaps field %s (%d-%d) without redefining it",
+			t.Field(i).Name, f.start-1, f.end, t.Field(j).Name, g.start-1, g.end)
+	}
+
+	return nil
+}
+
+// redefined returns the index of the field whose bytes field i occupies,
+// following redefinitions of redefinitions.
+func (sr structRepresentation) redefined(i int) int {
+	for sr.fields[i].redefines >= 0 {
+		i = sr.fields[i].redefines
+	}
+
+	return i
+}
+
 // cachedStructRepresentation is like makeStructRepresentation but cached to prevent duplicate work.
 func cachedStructRepresentation(t reflect.Type) structRepresentation {
 	if f, ok := fieldRepCache.Load(t); ok {
@@ -0,0 +1,97 @@
+package pic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Transform adjusts a field while it's decoded, e.g. upper-casing it, mapping
+// legacy codes, or stripping check digits. Raw, if set, rewrites the raw value,
+// with surrounding spaces trimmed, before it's checked for absence, parsed and
+// validated. Value, if set, adjusts the decoded value of the field after.
+type Transform struct {
+	Raw   func(s string) (string, error)
+	Value func(v reflect.Value) error
+}
+
+// WithTransform registers a transform for the field with the given path, e.g.
+// Status or Group.Code. Fields of slices of structs have a path without an
+// index, e.g. Items.Code, and transforms registered for the same path run in
+// the order they're registered.
+func WithTransform(path string, t Transform) Option {
+	return func(d *decoder) {
+		if d.transforms == nil {
+			d.transforms = make(map[string][]Transform)
+		}
+
+		d.transforms[path] = append(d.transforms[path], t)
+	}
+}
+
+// WithTagTransform registers a transform by name, which fields opt into with a
+// transform tag, e.g. `pic:"5" transform:"upper,legacy-codes"`. Tagged
+// transforms run in the order of the tag, before any registered for the
+// field's path.
+func WithTagTransform(name string, t Transform) Option {
+	return func(d *decoder) {
+		if d.tagTransforms == nil {
+			d.tagTransforms = make(map[string]Transform)
+		}
+
+		d.tagTransforms[name] = t
+	}
+}
+
+// transforming reports whether the decoder has any transforms registered.
+func (d *decoder) transforming() bool {
+	return d.transforms != nil || d.tagTransforms != nil
+}
+
+// transformsFor returns the transforms of the field with the given path, and
+// transform tag names.
+func (d *decoder) transformsFor(path string, names []string) ([]Transform, error) {
+	if len(names) == 0 {
+		return d.transforms[path], nil
+	}
+
+	ts := make([]Transform, 0, len(names)+len(d.transforms[path]))
+	for _, name := range names {
+		t, ok := d.tagTransforms[name]
+		if !ok {
+			return nil, fmt.Errorf("no transform is registered for %q", name)
+		}
+
+		ts = append(ts, t)
+	}
+
+	return append(ts, d.transforms[path]...), nil
+}
+
+func transformRaw(ts []Transform, s string) (string, error) {
+	for _, t := range ts {
+		if t.Raw == nil {
+			continue
+		}
+
+		var err error
+		if s, err = t.Raw(s); err != nil {
+			return "", fmt.Errorf("failed to transform value: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func transformValue(ts []Transform, v reflect.Value) error {
+	for _, t := range ts {
+		if t.Value == nil {
+			continue
+		}
+
+		if err := t.Value(v); err != nil {
+			return fmt.Errorf("failed to transform value: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package pic
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder_WithTransforms(t *testing.T) {
+	type item struct {
+		Code string `pic:"2"`
+	}
+
+	type group struct {
+		Status string `pic:"1" values:"ACTIVE,CLOSED"`
+	}
+
+	type record struct {
+		Name    string `pic:"5" transform:"upper"`
+		Account int    `pic:"6"`
+		Group   group  `pic:"1"`
+		Items   []item `pic:"2,2"`
+		Cents   int    `pic:"3"`
+	}
+
+	legacy := map[string]string{"A": "ACTIVE", "C": "CLOSED"}
+	opts := []Option{
+		WithValidation(),
+		WithTagTransform("upper", Transform{Raw: func(s string) (string, error) {
+			return strings.ToUpper(s), nil
+		}}),
+		WithTransform("Account", Transform{Raw: func(s string) (string, error) {
+			return s[:len(s)-1], nil // strip the check digit
+		}}),
+		WithTransform("Group.Status", Transform{Raw: func(s string) (string, error) {
+			return legacy[s], nil
+		}}),
+		WithTransform("Items.Code", Transform{Raw: func(s string) (string, error) {
+			return strings.ToLower(s), nil
+		}}),
+		WithTransform("Cents", Transform{Value: func(v reflect.Value) error {
+			v.SetInt(v.Int() * 10)
+			return nil
+		}}),
+	}
+
+	data := "alice123457CABXY042"
+	expected := record{"ALICE", 12345, group{"CLOSED"}, []item{{"ab"}, {"xy"}}, 420}
+
+	got := record{}
+	require.NoError(t, NewDecoder(strings.NewReader(data), opts...).Decode(&got))
+	require.Equal(t, expected, got)
+
+	c, err := Compile(reflect.TypeOf(record{}), opts...)
+	require.NoError(t, err)
+
+	got = record{}
+	require.NoError(t, c.DecodeBytes([]byte(data), &got))
+	require.Equal(t, expected, got)
+
+	t.Run("Failed", func(t *testing.T) {
+		failing := WithTransform("Cents", Transform{Value: func(reflect.Value) error {
+			return errors.New("too many cents")
+		}})
+
+		err := NewDecoder(strings.NewReader(data), append(opts, failing)...).Decode(&record{})
+		require.EqualError(t, err, "pic: cannot unmarshal "+data+" into Go struct field record.Cents of type int: "+
+			"failed to transform value: too many cents")
+
+		c, err := Compile(reflect.TypeOf(record{}), append(opts, failing)...)
+		require.NoError(t, err)
+		require.EqualError(t, c.DecodeBytes([]byte(data), &record{}), "pic: cannot unmarshal "+data+
+			" into Go struct field record.Cents of type int: failed to transform value: too many cents")
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		err := NewDecoder(strings.NewReader(data), WithTransform("Cents", Transform{})).Decode(&record{})
+		require.EqualError(t, err, "pic: cannot unmarshal "+data+" into Go struct field record.Name of type string: "+
+			`no transform is registered for "upper"`)
+
+		_, err = Compile(reflect.TypeOf(record{}), WithTransform("Cents", Transform{}))
+		require.EqualError(t, err, "pic: invalid Go struct field record.Name of type string: "+
+			`no transform is registered for "upper"`)
+	})
+}
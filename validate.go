@@ -0,0 +1,143 @@
+package pic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Validate checks the pic tags of v, which may be a pic tagged struct, a
+// pointer to one, or its reflect.Type, against the Go types of its fields, so
+// that mistakes are found up front rather than while decoding. It reports, as
+// TagErrors:
+//
+//   - tags that can't be parsed, and fields that overlap without redefining
+//   - integer fields with more digits than their Go type can hold, e.g.
+//     `pic:"3"` on an int8, which overflows for 999
+//   - lengths or occurs counts that aren't positive, and slice or array
+//     fields without an occurs count
+//   - unexported fields with a pic tag, which can't be set
+//   - fields of kinds that can't be decoded, such as maps or channels
+//   - interface fields without a select tag that are nil, which is every such
+//     field if v is a reflect.Type
+func Validate(v interface{}) error {
+	t, ok := v.(reflect.Type)
+	var rv reflect.Value
+	if !ok && v != nil {
+		rv = reflect.ValueOf(v)
+		t = rv.Type()
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if rv.IsValid() && !rv.IsNil() {
+			rv = rv.Elem()
+		} else {
+			rv = reflect.Value{}
+		}
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("pic: cannot validate non-struct type %v", t)
+	}
+
+	if errs := validateStruct(nil, t, rv); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateStruct appends a TagError for each invalid field of the struct type
+// t, and of the groups within it, to errs. v is the value of the struct, if
+// any.
+func validateStruct(errs TagErrors, t reflect.Type, v reflect.Value) TagErrors {
+	spec := cachedStructRepresentation(t)
+	for i, ff := range spec.fields {
+		f := t.Field(i)
+		err := ff.err
+		// interfaces with a select tag get their type while decoding
+		if err == nil && !ff.skip && ff.selector < 0 {
+			var fv reflect.Value
+			if v.IsValid() {
+				fv = v.Field(i)
+			}
+
+			err = validateType(f.Type, fv, ff.len, ff.occurs)
+		}
+
+		var nested TagErrors
+		switch {
+		case errors.As(err, &nested):
+			errs = append(errs, nested...)
+		case err != nil:
+			errs = append(errs, &TagError{f.Type, t.Name(), f.Name, err})
+		}
+	}
+
+	return errs
+}
+
+// validateType checks that a field of type t, and value v if any, can hold
+// length bytes of data, split into occurs occurrences for slices.
+func validateType(t reflect.Type, v reflect.Value, length, occurs int) error { // nolint:gocyclo
+	if isScanner(t) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64:
+		return nil
+
+	case reflect.Struct:
+		if errs := validateStruct(nil, t, v); len(errs) > 0 {
+			return errs
+		}
+
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return validateDigits(t, length, uint64(math.MaxInt64)>>(64-t.Bits()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return validateDigits(t, length, uint64(math.MaxUint64)>>(64-t.Bits()))
+
+	case reflect.Slice:
+		if occurs == 0 {
+			return errors.New("slice field has no occurs count")
+		}
+
+		return validateType(t.Elem(), reflect.Value{}, length/occurs, 0)
+
+	case reflect.Ptr:
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+
+		return validateType(t.Elem(), v, length, occurs)
+
+	case reflect.Interface:
+		if !v.IsValid() || v.IsNil() {
+			return errors.New("nil interface has no type to decode into")
+		}
+
+		return validateType(v.Elem().Type(), v.Elem(), length, occurs)
+	}
+
+	return fmt.Errorf("unsupported kind %s", t.Kind())
+}
+
+// validateDigits checks that every number of length digits fits in the
+// integer type t, whose largest value is max.
+func validateDigits(t reflect.Type, length int, max uint64) error {
+	digits := len(strconv.FormatUint(max, 10)) - 1
+	if length > digits {
+		return fmt.Errorf("%d digits overflow %s, which holds at most %d", length, t, digits)
+	}
+
+	return nil
+}
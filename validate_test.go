@@ -0,0 +1,143 @@
+package pic
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	type group struct {
+		Small int8 `pic:"3"`
+	}
+
+	type valid struct {
+		Name    string        `pic:"10"`
+		Small   int8          `pic:"2"`
+		Big     uint64        `pic:"19"`
+		Amount  float64       `pic:"12"`
+		Null    sql.NullInt64 `pic:"5"`
+		Ptr     *int32        `pic:"9"`
+		Codes   []uint16      `pic:"4,2"`
+		Any     interface{}   `pic:"4"`
+		Helper  map[string]int
+		_       string   `pic:"3"`
+		Skipped chan int `pic:"3,skip"`
+	}
+
+	type invalid struct {
+		Tag    string      `pic:"x"`
+		Null   string      `pic:"1" null:"nope"`
+		Small  int8        `pic:"3"`
+		Word   uint16      `pic:"5"`
+		Codes  []int       `pic:"4"`
+		Map    map[int]int `pic:"2"`
+		Any    interface{} `pic:"1"`
+		Group  group       `pic:"3"`
+		Groups []group     `pic:"3,2"`
+
+		Negative string   `pic:"-3"`
+		NoOccurs []string `pic:"2,0"`
+		Array    [2]int   `pic:"2"`
+		hidden   string   `pic:"2"` // nolint:unused,structcheck // only here to be rejected
+	}
+
+	require.NoError(t, Validate(valid{Any: ""}))
+	require.NoError(t, Validate(&valid{Any: new(int)}))
+
+	err := Validate(reflect.TypeOf(valid{}))
+	require.EqualError(t, err, "pic: invalid Go struct field valid.Any of type interface {}: "+
+		"nil interface has no type to decode into")
+
+	err = Validate(invalid{})
+	require.IsType(t, TagErrors{}, err)
+	require.Equal(t, []string{
+		`pic: invalid Go struct field invalid.Tag of type string: failed string->int conversion: ` +
+			`strconv.Atoi: parsing "x": invalid syntax`,
+		`pic: invalid Go struct field invalid.Null of type string: unknown null sentinel "nope"`,
+		`pic: invalid Go struct field invalid.Small of type int8: 3 digits overflow int8, which holds at most 2`,
+		`pic: invalid Go struct field invalid.Word of type uint16: 5 digits overflow uint16, which holds at most 4`,
+		`pic: invalid Go struct field invalid.Codes of type []int: slice field has no occurs count`,
+		`pic: invalid Go struct field invalid.Map of type map[int]int: unsupported kind map`,
+		`pic: invalid Go struct field invalid.Any of type interface {}: nil interface has no type to decode into`,
+		`pic: invalid Go struct field group.Small of type int8: 3 digits overflow int8, which holds at most 2`,
+		`pic: invalid Go struct field group.Small of type int8: 3 digits overflow int8, which holds at most 2`,
+		`pic: invalid Go struct field invalid.Negative of type string: length -3 is not positive`,
+		`pic: invalid Go struct field invalid.NoOccurs of type []string: occurs count 0 is not positive`,
+		`pic: invalid Go struct field invalid.Array of type [2]int: array field has no occurs count`,
+		`pic: invalid Go struct field invalid.hidden of type string: unexported field can't be decoded into`,
+	}, messages(err.(TagErrors)))
+
+	require.EqualError(t, Validate(""), "pic: cannot validate non-struct type string")
+	require.EqualError(t, Validate(nil), "pic: cannot validate non-struct type <nil>")
+}
+
+func TestUnmarshal_InvalidTag(t *testing.T) {
+	type invalid struct {
+		A string `pic:"2"`
+		B int    `pic:"two"`
+	}
+
+	err := Unmarshal([]byte("AB12"), &invalid{})
+	require.EqualError(t, err, `pic: invalid Go struct field invalid.B of type int: `+
+		`failed string->int conversion: strconv.Atoi: parsing "two": invalid syntax`)
+
+	_, err = Compile(reflect.TypeOf(invalid{}))
+	require.IsType(t, &TagError{}, err)
+
+	for _, v := range []interface{}{
+		&struct {
+			A []string `pic:"2"`
+		}{},
+		&struct {
+			A []string `pic:"2,0"`
+		}{},
+		&struct {
+			A string `pic:"-3"`
+		}{},
+		&struct {
+			A *[]string `pic:"4"`
+		}{},
+		&struct {
+			a string `pic:"2"` // nolint:unused,structcheck // only here to be rejected
+		}{},
+	} {
+		require.NotPanics(t, func() {
+			err = Unmarshal([]byte("ABCD"), v)
+		})
+		require.Error(t, err)
+
+		_, cerr := Compile(reflect.TypeOf(v))
+		require.Error(t, cerr)
+	}
+}
+
+func TestUnmarshal_Overflow(t *testing.T) {
+	type record struct {
+		Small int8  `pic:"3"`
+		Byte  uint8 `pic:"3"`
+	}
+
+	c, err := Compile(reflect.TypeOf(record{}))
+	require.NoError(t, err)
+
+	for _, val := range []string{"999000", "000300", "000-01"} {
+		require.Error(t, Unmarshal([]byte(val), &record{}), val)
+		require.Error(t, c.DecodeBytes([]byte(val), &record{}), val)
+	}
+
+	got := record{}
+	require.NoError(t, c.DecodeBytes([]byte("-12255"), &got))
+	require.Equal(t, record{-12, 255}, got)
+}
+
+func messages(errs TagErrors) []string {
+	ss := make([]string, len(errs))
+	for i, err := range errs {
+		ss[i] = err.Error()
+	}
+
+	return ss
+}
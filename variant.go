@@ -0,0 +1,84 @@
+package pic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithVariants registers the concrete types an interface field may be decoded
+// into, keyed by the value of the field named by its select tag, e.g.
+//
+//	type Record struct {
+//		Kind string `pic:"1"`
+//		Body Body   `pic:"20" select:"Kind"`
+//	}
+//
+//	d := pic.NewDecoder(r, pic.WithVariants((*Body)(nil), map[string]interface{}{
+//		"A": AccountBody{},
+//		"T": &TransferBody{},
+//	}))
+//
+// iface is a nil pointer to the interface type, and each variant is a value of
+// a type implementing it, whose fields are decoded from the interface field's
+// bytes. This lets REDEFINES sections that vary by record type decode into a
+// Go interface. If iface or a variant isn't one of these, Decode and Compile
+// return an error.
+func WithVariants(iface interface{}, variants map[string]interface{}) Option {
+	return func(d *decoder) {
+		if d.err != nil {
+			return
+		}
+
+		t := reflect.TypeOf(iface)
+		if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+			d.err = fmt.Errorf("pic: WithVariants needs a nil pointer to an interface type, not %T", iface)
+			return
+		}
+
+		if d.variants == nil {
+			d.variants = make(map[reflect.Type]map[string]variant)
+		}
+
+		t = t.Elem()
+		if d.variants[t] == nil {
+			d.variants[t] = make(map[string]variant, len(variants))
+		}
+
+		for key, v := range variants {
+			vt := reflect.TypeOf(v)
+			if vt == nil || !vt.Implements(t) {
+				d.err = fmt.Errorf("pic: variant %q of type %T does not implement %s", key, v, t)
+				return
+			}
+
+			d.variants[t][key] = variant{vt, kindSetFunc(vt, 0, 0, false)}
+		}
+	}
+}
+
+// variant is a concrete type an interface field may be decoded into.
+type variant struct {
+	typ reflect.Type
+	set setFunc
+}
+
+// setVariant decodes s into the interface v, as the variant registered for
+// the value key of its select field.
+func (d *decoder) setVariant(v reflect.Value, key, s string) error {
+	if d.null.isNull(s) {
+		return nilSetFunc(d, v, s)
+	}
+
+	vr, ok := d.variants[v.Type()][key]
+	if !ok {
+		return fmt.Errorf("no variant of %s is registered for %q", v.Type(), key)
+	}
+
+	nv := reflect.New(vr.typ).Elem()
+	if err := vr.set(d, nv, s); err != nil {
+		return err
+	}
+
+	v.Set(nv)
+	return nil
+}
@@ -0,0 +1,94 @@
+package pic
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type body interface{}
+
+type accountBody struct {
+	Number string `pic:"6"`
+	Branch int    `pic:"3"`
+}
+
+type transferBody struct {
+	Amount float64 `pic:"7"`
+	Code   string  `pic:"2"`
+}
+
+type variantRecord struct {
+	Kind string `pic:"1"`
+	Body body   `pic:"9" select:"Kind"`
+	Tail string `pic:"2"`
+}
+
+func TestDecoder_WithVariants(t *testing.T) {
+	variants := WithVariants((*body)(nil), map[string]interface{}{
+		"A": accountBody{},
+		"T": &transferBody{},
+	})
+
+	data := "AACC001042ZZ\nT0012.50XYZZ\n           \n"
+	expected := []variantRecord{
+		{"A", accountBody{"ACC001", 42}, "ZZ"},
+		{"T", &transferBody{12.5, "XY"}, "ZZ"},
+		{},
+	}
+
+	got := []variantRecord{}
+	require.NoError(t, NewDecoder(strings.NewReader(data), variants).Decode(&got))
+	require.Equal(t, expected, got)
+
+	c, err := Compile(reflect.TypeOf(variantRecord{}), variants)
+	require.NoError(t, err)
+	for i, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		rec := variantRecord{}
+		require.NoError(t, c.DecodeBytes([]byte(line), &rec))
+		require.Equal(t, expected[i], rec)
+	}
+
+	t.Run("UnknownVariant", func(t *testing.T) {
+		err := NewDecoder(strings.NewReader("XACC001042ZZ"), variants).Decode(&variantRecord{})
+		require.EqualError(t, err, "pic: cannot unmarshal XACC001042ZZ into Go struct field variantRecord.Body "+
+			`of type pic.body: no variant of pic.body is registered for "X"`)
+	})
+
+	t.Run("NoSelect", func(t *testing.T) {
+		type record struct {
+			Body body `pic:"3"`
+		}
+
+		err := Unmarshal([]byte("ABC"), &record{})
+		require.EqualError(t, err, "pic: cannot unmarshal ABC into Go struct field record.Body of type pic.body: "+
+			"pic: cannot decode into nil pic.body without a select tag")
+	})
+
+	t.Run("InvalidRegistration", func(t *testing.T) {
+		err := NewDecoder(strings.NewReader("AACC"), WithVariants(body(nil), nil)).Decode(&variantRecord{})
+		require.EqualError(t, err, "pic: WithVariants needs a nil pointer to an interface type, not <nil>")
+
+		bad := WithVariants((*error)(nil), map[string]interface{}{"A": accountBody{}})
+		_, err = Compile(reflect.TypeOf(variantRecord{}), bad)
+		require.EqualError(t, err, `pic: variant "A" of type pic.accountBody does not implement error`)
+
+		_, err = NewRecordReader(strings.NewReader(""), reflect.TypeOf(variantRecord{}), bad)
+		require.EqualError(t, err, `pic: variant "A" of type pic.accountBody does not implement error`)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, Validate(variantRecord{}))
+
+		type record struct {
+			Kind string `pic:"1" select:"Body"`
+			Body body   `pic:"3" select:"Missing"`
+		}
+
+		require.EqualError(t, Validate(record{}), "pic: invalid Go struct field record.Kind of type string: "+
+			"select tag on non-interface field Kind; pic: invalid Go struct field record.Body of type pic.body: "+
+			"select field Missing is not declared before Body")
+	})
+}